@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/catalog"
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+// batchRunner runs sync batches: it applies the dead-letter queue to the
+// hotel list, records one run per endpoint type and takes every hotel through
+// the endpoint types on the worker pool.
+type batchRunner struct {
+	cupidClient *client.Client
+	repository  *database.HotelRepository
+	concurrency int
+	policy      database.DeadLetterPolicy
+	goneGrace   time.Duration
+}
+
+// run syncs the hotels of source for endpointTypes, given in dependency order,
+// as one new sync run per endpoint type.
+func (b *batchRunner) run(ctx context.Context, endpointTypes []EndpointType, source catalog.Source) ([]*database.SyncRun, error) {
+	hotelIDs, err := source.Load(ctx, b.repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load hotel IDs: %w", err)
+	}
+
+	p := b.newPipeline()
+	var runs []*database.SyncRun
+	var dispatch []int
+	dispatched := make(map[int]bool)
+	for _, et := range endpointTypes {
+		letters, err := b.repository.ListDeadLetters(ctx, string(et), "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dead letters: %w", err)
+		}
+		plan := planBatch(hotelIDs, letters, time.Now())
+		if plan.Retried+plan.Deferred+plan.Parked > 0 {
+			log.Printf("Dead-letter queue (%s): retrying %d hotels, %d waiting for backoff, %d parked",
+				et, plan.Retried, plan.Deferred, plan.Parked)
+		}
+
+		run, err := b.repository.CreateSyncRun(ctx, string(et), plan.HotelIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s sync run: %w", et, err)
+		}
+		log.Printf("Created %s sync run %d of %d hotels", et, run.ID, len(plan.HotelIDs))
+		runs = append(runs, run)
+		b.addStep(ctx, p, run, plan.HotelIDs)
+
+		for _, hotelID := range plan.HotelIDs {
+			if !dispatched[hotelID] {
+				dispatched[hotelID] = true
+				dispatch = append(dispatch, hotelID)
+			}
+		}
+	}
+
+	log.Printf("Starting sync of %d hotels from %s with %d workers", len(dispatch), source.Description(), b.concurrency)
+	return b.execute(ctx, p, runs, dispatch)
+}
+
+// resume continues an interrupted sync run with the hotels it has left.
+func (b *batchRunner) resume(ctx context.Context, runID int64) ([]*database.SyncRun, error) {
+	run, hotelIDs, err := b.repository.ResumeSyncRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume sync run %d: %w", runID, err)
+	}
+	log.Printf("Resuming sync run %d (%s): %d of %d hotels left with %d workers",
+		run.ID, run.EndpointType, len(hotelIDs), run.Total, b.concurrency)
+
+	p := b.newPipeline()
+	b.addStep(ctx, p, run, hotelIDs)
+	return b.execute(ctx, p, []*database.SyncRun{run}, hotelIDs)
+}
+
+func (b *batchRunner) newPipeline() *pipeline {
+	return &pipeline{syncer: &syncer{cupidClient: b.cupidClient, repository: b.repository, goneGrace: b.goneGrace}}
+}
+
+// addStep adds the endpoint type of run to the pipeline, limited to hotelIDs
+// and recording every outcome in the run.
+func (b *batchRunner) addStep(ctx context.Context, p *pipeline, run *database.SyncRun, hotelIDs []int) {
+	hotels := make(map[int]bool, len(hotelIDs))
+	for _, hotelID := range hotelIDs {
+		hotels[hotelID] = true
+	}
+	endpointType := EndpointType(run.EndpointType)
+	p.steps = append(p.steps, &pipelineStep{
+		endpointType: endpointType,
+		hotels:       hotels,
+		record:       newRunRecorder(ctx, b.repository, run.ID, endpointType, b.policy).record,
+	})
+}
+
+// execute syncs hotelIDs through the pipeline, closes its runs and reports the
+// status of every step.
+func (b *batchRunner) execute(ctx context.Context, p *pipeline, runs []*database.SyncRun, hotelIDs []int) ([]*database.SyncRun, error) {
+	summary := runBatch(ctx, hotelIDs, b.concurrency, p.syncHotel)
+	log.Printf("Batch sync completed in %v: %d hotels successful, %d failed, %d skipped",
+		summary.Elapsed.Round(time.Millisecond), summary.Successful, summary.Failed, summary.Skipped)
+
+	var stopped string
+	if summary.Stopped != nil {
+		stopped = summary.Stopped.Error()
+	}
+
+	finished := make([]*database.SyncRun, 0, len(runs))
+	var errs []error
+	for _, run := range runs {
+		run, err := b.repository.FinishSyncRun(context.WithoutCancel(ctx), run.ID, stopped)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to finish sync run: %w", err))
+			continue
+		}
+		finished = append(finished, run)
+		log.Printf("%-12s sync run %d %s: %d succeeded, %d failed, %d blocked, %d skipped",
+			run.EndpointType, run.ID, run.Status, run.Successful, run.Failed, run.Blocked, run.Skipped)
+		if run.Status == database.SyncRunInterrupted {
+			log.Printf("%d %s hotels left, continue with --resume %d", run.Skipped, run.EndpointType, run.ID)
+		}
+	}
+	return finished, errors.Join(errs...)
+}
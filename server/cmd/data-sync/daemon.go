@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/catalog"
+	"github.com/vrnvu/cupid/internal/database"
+	"github.com/vrnvu/cupid/internal/schedule"
+)
+
+// daemonLockKey is the Postgres advisory lock held by the data-sync leader.
+const daemonLockKey int64 = 0x6375706964 // "cupid"
+
+// scheduledJob syncs one endpoint type on a cron schedule.
+type scheduledJob struct {
+	endpointType EndpointType
+	schedule     *schedule.Schedule
+	next         time.Time
+}
+
+// daemon runs scheduled syncs. Several replicas may run it; only the one
+// holding the advisory lock schedules syncs, the others wait to take over.
+type daemon struct {
+	db     *database.DB
+	runner *batchRunner
+	source catalog.Source
+	jobs   []*scheduledJob
+	// jitter delays every scheduled run by a random duration up to this value.
+	jitter time.Duration
+	// leaderCheck is how often standby replicas retry the lock and the leader checks it still holds it.
+	leaderCheck time.Duration
+}
+
+// newDaemon parses the cron expression of every endpoint type. Endpoint types
+// with an empty expression are not scheduled.
+func newDaemon(db *database.DB, runner *batchRunner, source catalog.Source, schedules map[EndpointType]string, jitter time.Duration) (*daemon, error) {
+	d := &daemon{
+		db:          db,
+		runner:      runner,
+		source:      source,
+		jitter:      jitter,
+		leaderCheck: 30 * time.Second,
+	}
+	for _, et := range endpointOrder {
+		expr := schedules[et]
+		if expr == "" {
+			continue
+		}
+		s, err := schedule.Parse(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s schedule: %w", et, err)
+		}
+		if s.Next(time.Now()).IsZero() {
+			return nil, fmt.Errorf("%s schedule %q never runs", et, expr)
+		}
+		d.jobs = append(d.jobs, &scheduledJob{endpointType: et, schedule: s})
+	}
+	if len(d.jobs) == 0 {
+		return nil, fmt.Errorf("no endpoint type is scheduled")
+	}
+	return d, nil
+}
+
+// run campaigns for leadership and schedules syncs while leader, until ctx is cancelled.
+func (d *daemon) run(ctx context.Context) {
+	log.Printf("Data sync daemon started, waiting for leadership")
+	for {
+		lock, err := d.db.TryAdvisoryLock(ctx, daemonLockKey)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Printf("Warning: Failed to acquire leader lock: %v", err)
+		case lock != nil:
+			log.Printf("Acquired leadership")
+			err := d.lead(ctx, lock)
+
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			if relErr := lock.Release(releaseCtx); relErr != nil {
+				log.Printf("Warning: Failed to release leader lock: %v", relErr)
+			}
+			cancel()
+
+			if ctx.Err() == nil {
+				log.Printf("Warning: Lost leadership: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Data sync daemon stopped")
+			return
+		case <-time.After(d.leaderCheck):
+		}
+	}
+}
+
+// lead runs the scheduled jobs one at a time until ctx is cancelled or the
+// lock is lost. Runs missed while not leader are skipped: every job starts at
+// its first slot after leadership is taken. A job that comes due while another
+// runs starts as soon as that one finishes, once however many of its slots
+// passed meanwhile.
+func (d *daemon) lead(ctx context.Context, lock *database.AdvisoryLock) error {
+	d.startTerm(time.Now())
+
+	check := time.NewTicker(d.leaderCheck)
+	defer check.Stop()
+
+	for {
+		job := d.nextJob()
+		timer := time.NewTimer(time.Until(job.next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-check.C:
+			timer.Stop()
+			if err := lock.Ping(ctx); err != nil {
+				return fmt.Errorf("leader lock connection failed: %w", err)
+			}
+			continue
+		case <-timer.C:
+		}
+
+		if err := lock.Ping(ctx); err != nil {
+			return fmt.Errorf("leader lock connection failed: %w", err)
+		}
+
+		log.Printf("Running scheduled %s sync", job.endpointType)
+		if _, err := d.runner.run(ctx, []EndpointType{job.endpointType}, d.source); err != nil {
+			log.Printf("Warning: Scheduled %s sync failed: %v", job.endpointType, err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		job.next = d.nextRun(job, time.Now())
+		log.Printf("Next %s sync at %s", job.endpointType, job.next.Format(time.RFC3339))
+	}
+}
+
+// startTerm schedules every job at its first slot strictly after now.
+func (d *daemon) startTerm(now time.Time) {
+	for _, job := range d.jobs {
+		job.next = d.nextRun(job, now)
+		log.Printf("Next %s sync at %s (%s)", job.endpointType, job.next.Format(time.RFC3339), job.schedule)
+	}
+}
+
+// nextJob returns the job due first.
+func (d *daemon) nextJob() *scheduledJob {
+	job := d.jobs[0]
+	for _, j := range d.jobs[1:] {
+		if j.next.Before(job.next) {
+			job = j
+		}
+	}
+	return job
+}
+
+func (d *daemon) nextRun(job *scheduledJob, now time.Time) time.Time {
+	next := job.schedule.Next(now)
+	if d.jitter > 0 {
+		next = next.Add(rand.N(d.jitter))
+	}
+	return next
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/catalog"
+)
+
+func TestDaemon_StartTermSkipsMissedRuns(t *testing.T) {
+	t.Parallel()
+
+	d, err := newDaemon(nil, nil, catalog.Source{}, map[EndpointType]string{
+		ContentEndpoint: "0 * * * *",
+		ReviewsEndpoint: "30 * * * *",
+	}, 0)
+	require.NoError(t, err)
+
+	// The previous leader's slots at 10:00 and 10:30 passed while this
+	// replica was on standby.
+	previous := time.Date(2025, 1, 15, 9, 45, 0, 0, time.UTC)
+	d.startTerm(previous)
+	takeover := time.Date(2025, 1, 15, 10, 40, 0, 0, time.UTC)
+	d.startTerm(takeover)
+
+	job := d.nextJob()
+	assert.Equal(t, ContentEndpoint, job.endpointType)
+	assert.Equal(t, time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC), job.next, "missed runs are not caught up")
+	for _, job := range d.jobs {
+		assert.True(t, job.next.After(takeover))
+	}
+}
+
+func TestDaemon_NextJobRunsOverdueJobOnce(t *testing.T) {
+	t.Parallel()
+
+	d, err := newDaemon(nil, nil, catalog.Source{}, map[EndpointType]string{
+		ContentEndpoint: "0 * * * *",
+		ReviewsEndpoint: "*/5 * * * *",
+	}, 0)
+	require.NoError(t, err)
+	start := time.Date(2025, 1, 15, 9, 58, 0, 0, time.UTC)
+	d.startTerm(start)
+
+	// Both jobs are due at 10:00; the content sync runs first, until 10:17.
+	job := d.nextJob()
+	require.Equal(t, ContentEndpoint, job.endpointType)
+	finished := time.Date(2025, 1, 15, 10, 17, 0, 0, time.UTC)
+	job.next = d.nextRun(job, finished)
+
+	// The reviews sync, overdue since 10:00, runs right away, once, and then
+	// resumes its schedule.
+	job = d.nextJob()
+	assert.Equal(t, ReviewsEndpoint, job.endpointType)
+	assert.Equal(t, time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), job.next)
+	job.next = d.nextRun(job, finished.Add(time.Minute))
+	assert.Equal(t, time.Date(2025, 1, 15, 10, 20, 0, 0, time.UTC), job.next)
+	assert.Equal(t, ReviewsEndpoint, d.nextJob().endpointType)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+// errorClass buckets a sync error for the dead-letter queue.
+func errorClass(err error) string {
+	var apiErr *client.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var storeErr *storeError
+
+	switch {
+	case errors.As(err, &storeErr):
+		return "storage"
+	case errors.As(err, &apiErr):
+		switch {
+		case apiErr.StatusCode == http.StatusNotFound:
+			return "not_found"
+		case apiErr.StatusCode == http.StatusUnauthorized, apiErr.StatusCode == http.StatusForbidden:
+			return "unauthorized"
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return "rate_limited"
+		case apiErr.StatusCode >= 500:
+			return "server_error"
+		default:
+			return "client_error"
+		}
+	case errors.Is(err, client.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return "parse"
+	case client.IsRetryable(err):
+		return "network"
+	default:
+		return "unknown"
+	}
+}
+
+// batchPlan is the list of hotels a batch syncs once the dead-letter queue is applied.
+type batchPlan struct {
+	HotelIDs []int
+	// Retried are listed hotels whose dead letter is due again.
+	Retried int
+	// Deferred are hotels still waiting for their backoff to elapse.
+	Deferred int
+	// Parked are hotels that reached the attempt limit.
+	Parked int
+}
+
+// planBatch drops parked hotels and hotels whose next attempt is not due yet
+// from hotelIDs. Dead letters of hotels missing from hotelIDs are left alone:
+// a run of explicit IDs syncs only those hotels, and hotels removed or
+// deactivated in the catalog are not retried. Catalog runs list every active
+// hotel, so all their due dead letters are retried.
+func planBatch(hotelIDs []int, letters []database.DeadLetter, now time.Time) batchPlan {
+	byHotel := make(map[int]database.DeadLetter, len(letters))
+	for _, letter := range letters {
+		byHotel[letter.HotelID] = letter
+	}
+
+	var plan batchPlan
+	for _, hotelID := range hotelIDs {
+		letter, ok := byHotel[hotelID]
+		switch {
+		case !ok:
+			plan.HotelIDs = append(plan.HotelIDs, hotelID)
+		case letter.Status == database.DeadLetterParked:
+			plan.Parked++
+		case letter.NextAttemptAt == nil || !letter.NextAttemptAt.After(now):
+			plan.HotelIDs = append(plan.HotelIDs, hotelID)
+			plan.Retried++
+		default:
+			plan.Deferred++
+		}
+	}
+	return plan
+}
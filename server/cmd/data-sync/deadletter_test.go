@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+func TestPlanBatch(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+	letters := []database.DeadLetter{
+		{HotelID: 2, Status: database.DeadLetterRetrying, NextAttemptAt: &past},
+		{HotelID: 3, Status: database.DeadLetterRetrying, NextAttemptAt: &future},
+		{HotelID: 4, Status: database.DeadLetterParked},
+		// Due, but not listed: deactivated in the catalog, or not asked for.
+		{HotelID: 9, Status: database.DeadLetterRetrying, NextAttemptAt: &past},
+	}
+
+	tests := []struct {
+		name     string
+		hotelIDs []int
+		want     batchPlan
+	}{
+		{
+			name:     "catalog run",
+			hotelIDs: []int{1, 2, 3, 4},
+			want:     batchPlan{HotelIDs: []int{1, 2}, Retried: 1, Deferred: 1, Parked: 1},
+		},
+		{
+			name:     "explicit ids",
+			hotelIDs: []int{1},
+			want:     batchPlan{HotelIDs: []int{1}},
+		},
+		{
+			name:     "hotel removed from the catalog",
+			hotelIDs: []int{1, 2},
+			want:     batchPlan{HotelIDs: []int{1, 2}, Retried: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, planBatch(tc.hotelIDs, letters, now))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/catalog"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+const dlqUsage = `usage: data-sync dlq <list|retry|purge> [flags]
+
+  list   show dead-lettered hotels
+  retry  make dead-lettered hotels due on the next sync run, unparking them
+  purge  delete dead-lettered hotels (requires a filter or -all)`
+
+// runDeadLetterCommand implements the dlq subcommand.
+func runDeadLetterCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(dlqUsage)
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("dlq "+action, flag.ExitOnError)
+	endpointType := fs.String("e", "", "Only entries of this endpoint type: content, reviews, or translations")
+	status := fs.String("status", "", "Only entries with this status: retrying or parked")
+	ids := fs.String("ids", "", "Only these comma separated hotel IDs")
+	all := fs.Bool("all", false, "Purge every entry")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch EndpointType(*endpointType) {
+	case "", ContentEndpoint, ReviewsEndpoint, TranslationsEndpoint:
+	default:
+		return fmt.Errorf("invalid endpoint type: %s. Must be one of: content, reviews, translations", *endpointType)
+	}
+	switch *status {
+	case "", database.DeadLetterRetrying, database.DeadLetterParked:
+	default:
+		return fmt.Errorf("invalid status: %s. Must be one of: retrying, parked", *status)
+	}
+	hotelIDs, err := catalog.ParseIDs(*ids)
+	if err != nil {
+		return err
+	}
+
+	db, err := connectDB()
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	repository := database.NewHotelRepository(db)
+	ctx := context.Background()
+
+	switch action {
+	case "list":
+		letters, err := repository.ListDeadLetters(ctx, *endpointType, *status)
+		if err != nil {
+			return err
+		}
+		printDeadLetters(letters, hotelIDs)
+	case "retry":
+		n, err := repository.RequeueDeadLetters(ctx, *endpointType, *status, hotelIDs)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Requeued %d dead-lettered hotels for the next sync run\n", n)
+	case "purge":
+		if !*all && *endpointType == "" && *status == "" && len(hotelIDs) == 0 {
+			return errors.New("refusing to purge every entry without -all")
+		}
+		n, err := repository.PurgeDeadLetters(ctx, *endpointType, *status, hotelIDs)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Purged %d dead-lettered hotels\n", n)
+	default:
+		return fmt.Errorf("unknown dlq action %q\n%s", action, dlqUsage)
+	}
+	return nil
+}
+
+func printDeadLetters(letters []database.DeadLetter, hotelIDs []int) {
+	only := make(map[int]bool, len(hotelIDs))
+	for _, hotelID := range hotelIDs {
+		only[hotelID] = true
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "HOTEL\tENDPOINT\tSTATUS\tATTEMPTS\tCLASS\tHTTP\tNEXT ATTEMPT\tLAST ERROR")
+	shown := 0
+	for _, letter := range letters {
+		if len(only) > 0 && !only[letter.HotelID] {
+			continue
+		}
+		next := "-"
+		if letter.NextAttemptAt != nil {
+			next = letter.NextAttemptAt.Local().Format(time.DateTime)
+		}
+		httpStatus := "-"
+		if letter.HTTPStatus != 0 {
+			httpStatus = fmt.Sprint(letter.HTTPStatus)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n", letter.HotelID, letter.EndpointType, letter.Status,
+			letter.Attempts, letter.ErrorClass, httpStatus, next, truncateText(letter.LastError, 80))
+		shown++
+	}
+	w.Flush()
+	fmt.Printf("%d dead-lettered hotels\n", shown)
+}
+
+func truncateText(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit-3] + "..."
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+	"github.com/vrnvu/cupid/internal/importer"
+)
+
+const importUsage = `usage: data-sync import [flags] <file|directory|->...
+
+Imports Cupid API payloads without calling the API: property objects, review
+and translation arrays, {"hotel_id": ..., "reviews"|"translations": [...]}
+envelopes and wiremock mappings, as JSON documents or NDJSON (.ndjson, .jsonl).
+Directories are read recursively.`
+
+// runImportCommand implements the import subcommand.
+func runImportCommand(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	kindFlag := fs.String("type", "auto", "Payload type: auto, property, reviews, or translations")
+	hotelID := fs.Int("hotel-id", 0, "Hotel the imported reviews and translations belong to, overriding the payload")
+	dryRun := fs.Bool("dry-run", false, "Parse and validate without storing")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), importUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New(importUsage)
+	}
+
+	kind, err := importer.ParseKind(*kindFlag)
+	if err != nil {
+		return err
+	}
+	files, err := importer.Files(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	imp := &payloadImport{
+		ctx:          ctx,
+		reviews:      make(map[int][]client.Review),
+		translations: make(map[int][]client.Translation),
+		sources:      make(map[string][]string),
+	}
+	if !*dryRun {
+		db, err := connectDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+		repository := database.NewHotelRepository(db)
+		defer connectCacheInvalidation(repository)()
+		imp.repository = repository
+	}
+
+	for _, file := range files {
+		if err := imp.readFile(file, importer.Options{Kind: kind, HotelID: *hotelID, NDJSON: importer.IsNDJSON(file)}); err != nil {
+			return err
+		}
+	}
+	imp.flush()
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Validated"
+	}
+	fmt.Printf("%s %d properties, reviews of %d hotels (%d reviews), translations of %d hotels (%d translations); %d skipped, %d failed\n",
+		verb, imp.properties, imp.reviewHotels, imp.reviewCount, imp.translationHotels, imp.translationCount, imp.skipped, imp.failed)
+	if imp.failed > 0 {
+		return fmt.Errorf("%d records failed to import", imp.failed)
+	}
+	return nil
+}
+
+// payloadImport stores imported records. Properties are stored as they are
+// read; reviews and translations are merged per hotel and stored once every
+// file is read, so a hotel's reviews may span several records and are stored
+// after the property they reference.
+type payloadImport struct {
+	ctx context.Context
+	// repository is nil on dry runs.
+	repository database.Repository
+
+	reviews      map[int][]client.Review
+	translations map[int][]client.Translation
+	reviewOrder  []int
+	transOrder   []int
+	// sources maps "kind/hotel" to the records it was merged from, for error reports.
+	sources map[string][]string
+
+	properties, reviewHotels, reviewCount                int
+	translationHotels, translationCount, skipped, failed int
+}
+
+func (p *payloadImport) readFile(file string, opts importer.Options) error {
+	var r io.Reader = os.Stdin
+	name := "stdin"
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", file, err)
+		}
+		defer f.Close()
+		r, name = f, file
+	}
+	if err := importer.Decode(r, name, opts, p.add); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
+
+func (p *payloadImport) add(record importer.Record) error {
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+
+	switch {
+	case record.Err != nil:
+		p.fail(record.Source, record.Err)
+	case record.Skipped != "":
+		log.Printf("%s: skipped: %s", record.Source, record.Skipped)
+		p.skipped++
+	case record.Kind == importer.KindProperty:
+		if p.repository != nil {
+			if err := p.repository.StoreProperty(p.ctx, record.Property); err != nil {
+				p.fail(record.Source, fmt.Errorf("failed to store property %d: %w", record.HotelID, err))
+				return nil
+			}
+		}
+		p.properties++
+	case record.Kind == importer.KindReviews:
+		if _, ok := p.reviews[record.HotelID]; !ok {
+			p.reviewOrder = append(p.reviewOrder, record.HotelID)
+		}
+		p.reviews[record.HotelID] = append(p.reviews[record.HotelID], record.Reviews...)
+		key := sourceKey(importer.KindReviews, record.HotelID)
+		p.sources[key] = append(p.sources[key], record.Source)
+	case record.Kind == importer.KindTranslations:
+		if _, ok := p.translations[record.HotelID]; !ok {
+			p.transOrder = append(p.transOrder, record.HotelID)
+		}
+		p.translations[record.HotelID] = append(p.translations[record.HotelID], record.Translations...)
+		key := sourceKey(importer.KindTranslations, record.HotelID)
+		p.sources[key] = append(p.sources[key], record.Source)
+	}
+	return nil
+}
+
+// flush stores the merged reviews and translations of every hotel.
+func (p *payloadImport) flush() {
+	for _, hotelID := range p.reviewOrder {
+		reviews := p.reviews[hotelID]
+		if p.repository != nil {
+			if err := p.repository.StoreReviews(p.ctx, hotelID, reviews); err != nil {
+				p.fail(strings.Join(p.sources[sourceKey(importer.KindReviews, hotelID)], ", "),
+					fmt.Errorf("failed to store reviews of hotel %d: %w", hotelID, err))
+				continue
+			}
+		}
+		p.reviewHotels++
+		p.reviewCount += len(reviews)
+	}
+
+	for _, hotelID := range p.transOrder {
+		translations := p.translations[hotelID]
+		if p.repository != nil {
+			if err := p.repository.StoreTranslations(p.ctx, hotelID, translations); err != nil {
+				p.fail(strings.Join(p.sources[sourceKey(importer.KindTranslations, hotelID)], ", "),
+					fmt.Errorf("failed to store translations of hotel %d: %w", hotelID, err))
+				continue
+			}
+		}
+		p.translationHotels++
+		p.translationCount += len(translations)
+	}
+}
+
+func (p *payloadImport) fail(source string, err error) {
+	log.Printf("%s: %v", source, err)
+	p.failed++
+}
+
+func sourceKey(kind importer.Kind, hotelID int) string {
+	return fmt.Sprintf("%s/%d", kind, hotelID)
+}
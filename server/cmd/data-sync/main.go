@@ -3,24 +3,22 @@ package main
 import (
 	"context"
 	"flag"
-	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
+	"github.com/vrnvu/cupid/internal/cache"
+	"github.com/vrnvu/cupid/internal/catalog"
 	"github.com/vrnvu/cupid/internal/client"
 	"github.com/vrnvu/cupid/internal/database"
 	"github.com/vrnvu/cupid/internal/telemetry"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-var allHotelIDs = []int{
-	1641879, 317597, 1202743, 1037179, 1154868, 1270324, 1305326, 1617655, 1975211, 2017823, 1503950, 1033299, 378772, 1563003, 1085875, 828917, 830417, 838887, 1702062, 1144294, 1738870, 898052, 906450, 906467, 2241195, 1244595, 1277032, 956026, 957111, 152896, 896868, 982911, 986491, 986622, 988544, 989315, 989544, 990223, 990341, 990370, 990490, 990609, 990629, 1259611, 991819, 992027, 992851, 993851, 994085, 994333, 994495, 994903, 995227, 995787, 996977, 1186578, 999444, 1000017, 1000051, 1198750, 1001100, 1001296, 1001402, 1002200, 1003142, 1004288, 1006404, 1006602, 1006810, 1006887, 1007101, 1007269, 1007466, 1011203, 1011644, 1011945, 1012047, 1012140, 1012944, 1023527, 1013529, 1013584, 1014383, 1015094, 1016591, 1016611, 1017019, 1017039, 1017044, 1018030, 1018130, 1018251, 1018402, 1018946, 1019473, 1020332, 1020335, 1020386, 1021856, 1022380,
-}
-
 type EndpointType string
 
 const (
@@ -30,13 +28,85 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		var command func([]string) error
+		switch os.Args[1] {
+		case "dlq":
+			command = runDeadLetterCommand
+		case "import":
+			command = runImportCommand
+		case "replay":
+			command = runReplayCommand
+		case "warm":
+			command = runWarmCommand
+		}
+		if command != nil {
+			if err := command(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
 	var endpointType string
-	flag.StringVar(&endpointType, "e", "content", "Endpoint type: content, reviews, or translations")
+	var conditional string
+	var conditionalDir string
+	var archive string
+	var archiveDir string
+	var rps float64
+	var burst int
+	var dailyBudget int
+	var concurrency int
+	var source catalog.Source
+	var resumeRunID int64
+	var daemonMode bool
+	var jitter time.Duration
+	var goneGrace time.Duration
+	schedules := map[EndpointType]string{
+		ContentEndpoint:      "0 2 * * *",
+		ReviewsEndpoint:      "0 * * * *",
+		TranslationsEndpoint: "0 3 * * 0",
+	}
+	deadLetterPolicy := database.DefaultDeadLetterPolicy()
+	flag.StringVar(&endpointType, "e", "content", "Endpoint types: content, reviews, translations, a comma separated list or all")
+	flag.StringVar(&conditional, "conditional", "off", "Conditional requests validator store: off, file, or postgres")
+	flag.StringVar(&conditionalDir, "conditional-dir", ".cache/cupid-validators", "Directory of the file validator store")
+	flag.StringVar(&archive, "archive", "off", "Raw payload archive: off, file, or postgres")
+	flag.StringVar(&archiveDir, "archive-dir", ".cache/cupid-payloads", "Directory of the file payload archive")
+	flag.Float64Var(&rps, "rps", 10, "Maximum Cupid API requests per second")
+	flag.IntVar(&burst, "burst", 1, "Burst size of the Cupid API rate limiter")
+	flag.IntVar(&dailyBudget, "daily-budget", 0, "Maximum Cupid API requests per day, 0 for unlimited")
+	flag.IntVar(&concurrency, "concurrency", 4, "Number of hotels synced in parallel")
+	flag.StringVar(&source.IDs, "ids", "", "Comma separated hotel IDs to sync")
+	flag.StringVar(&source.File, "ids-file", "", "CSV or NDJSON file of hotel IDs to sync")
+	flag.BoolVar(&source.FromDB, "from-db", false, "Sync the active hotels of the hotel_catalog table (default when no IDs are given)")
+	flag.Int64Var(&resumeRunID, "resume", 0, "Resume the interrupted sync run with this ID")
+	flag.IntVar(&deadLetterPolicy.MaxAttempts, "dlq-max-attempts", deadLetterPolicy.MaxAttempts, "Failed attempts after which a hotel is parked")
+	flag.DurationVar(&deadLetterPolicy.BaseDelay, "dlq-base-delay", deadLetterPolicy.BaseDelay, "Backoff before retrying a failed hotel, doubled on every attempt")
+	flag.DurationVar(&deadLetterPolicy.MaxDelay, "dlq-max-delay", deadLetterPolicy.MaxDelay, "Maximum backoff before retrying a failed hotel")
+	flag.DurationVar(&goneGrace, "gone-grace", 72*time.Hour, "How long a hotel must keep returning 404 before it is soft-deleted")
+	flag.BoolVar(&daemonMode, "daemon", false, "Keep running and sync every endpoint type on its schedule")
+	flag.Func("schedule-content", "Cron schedule of content syncs in daemon mode, empty to disable (default \"0 2 * * *\")", scheduleFlag(schedules, ContentEndpoint))
+	flag.Func("schedule-reviews", "Cron schedule of reviews syncs in daemon mode, empty to disable (default \"0 * * * *\")", scheduleFlag(schedules, ReviewsEndpoint))
+	flag.Func("schedule-translations", "Cron schedule of translations syncs in daemon mode, empty to disable (default \"0 3 * * 0\")", scheduleFlag(schedules, TranslationsEndpoint))
+	flag.DurationVar(&jitter, "jitter", 5*time.Minute, "Maximum random delay added to every scheduled sync in daemon mode")
 	flag.Parse()
 
-	et := EndpointType(endpointType)
-	if et != ContentEndpoint && et != ReviewsEndpoint && et != TranslationsEndpoint {
-		log.Fatalf("Invalid endpoint type: %s. Must be one of: content, reviews, translations", endpointType)
+	if resumeRunID > 0 && source != (catalog.Source{}) {
+		log.Fatal("--resume cannot be combined with --ids, --ids-file or --from-db")
+	}
+	if daemonMode && (resumeRunID > 0 || os.Getenv("HOTEL_ID") != "") {
+		log.Fatal("--daemon cannot be combined with --resume or HOTEL_ID")
+	}
+
+	// Interrupted batches stop dispatching, record the remaining hotels as skipped and can be resumed.
+	// In daemon mode the leader also releases its lock so a standby replica takes over.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	endpointTypes, err := parseEndpointTypes(endpointType)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	cupidSandboxAPI, ok := os.LookupEnv("CUPID_SANDBOX_API")
@@ -63,205 +133,135 @@ func main() {
 		baseURL = "https://content-api.cupid.travel"
 	}
 
-	singleHotelID := os.Getenv("HOTEL_ID")
-	if singleHotelID != "" {
-		hotelID, err := strconv.Atoi(singleHotelID)
-		if err != nil {
-			log.Fatalf("invalid hotel ID: %s", singleHotelID)
-		}
-		log.Printf("Starting sync for hotel %d", hotelID)
-		if err := syncHotel(context.Background(), hotelID, baseURL, cupidSandboxAPI, et); err != nil {
-			log.Printf("Failed to sync hotel %d: %v", hotelID, err)
-		}
-		log.Printf("Completed sync for hotel %d", hotelID)
-	} else {
-		log.Printf("Starting batch sync of %d hotels", len(allHotelIDs))
-		successCount := 0
-
-		for i, hotelID := range allHotelIDs {
-			log.Printf("Processing hotel %d (%d/%d)", hotelID, i+1, len(allHotelIDs))
-			if err := syncHotel(context.Background(), hotelID, baseURL, cupidSandboxAPI, et); err == nil {
-				successCount++
-			} else {
-				log.Printf("Failed to sync hotel %d: %v", hotelID, err)
-			}
-			time.Sleep(100 * time.Millisecond)
-		}
-
-		log.Printf("Batch sync completed: %d successful, %d failed", successCount, len(allHotelIDs)-successCount)
+	// One transport for all workers, keeping enough idle connections to the Cupid host.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = concurrency
+
+	clientOpts := []client.Option{
+		client.WithAPIKey(cupidSandboxAPI),
+		client.WithRetryPolicy(client.DefaultRetryPolicy()),
+		client.WithCircuitBreaker(client.DefaultBreakerSettings()),
+		client.WithRateLimit(rps, burst),
+		client.WithHTTPClient(&http.Client{
+			Transport: otelhttp.NewTransport(transport),
+			Timeout:   15 * time.Second,
+		}),
 	}
-}
 
-func syncHotel(ctx context.Context, hotelID int, baseURL, apiKey string, endpointType EndpointType) error {
-	dbConfig := database.Config{
-		Host:     getEnvOrDefault("DB_HOST", "localhost"),
-		Port:     5432,
-		User:     getEnvOrDefault("DB_USER", "cupid"),
-		Password: getEnvOrDefault("DB_PASSWORD", "cupid123"),
-		DBName:   getEnvOrDefault("DB_NAME", "cupid"),
-		SSLMode:  getEnvOrDefault("DB_SSLMODE", "disable"),
+	if dailyBudget > 0 {
+		clientOpts = append(clientOpts, client.WithRequestBudget(client.NewDailyBudget(dailyBudget)))
 	}
 
-	db, err := database.NewConnection(dbConfig)
+	db, err := connectDB()
 	if err != nil {
-		return fmt.Errorf("failed to connect to database: %w", err)
+		log.Fatalf("failed to connect to database: %v", err)
 	}
 	defer db.Close()
 
-	repository := database.NewHotelRepository(db)
-
-	httpClient := &http.Client{
-		Transport: otelhttp.NewTransport(http.DefaultTransport),
-	}
-
-	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
-	defer cancel()
-
-	switch endpointType {
-	case ContentEndpoint:
-		return syncHotelContent(ctx, httpClient, baseURL, apiKey, hotelID, repository)
-	case ReviewsEndpoint:
-		return syncHotelReviews(ctx, httpClient, baseURL, apiKey, hotelID, repository)
-	case TranslationsEndpoint:
-		return syncHotelTranslations(ctx, httpClient, baseURL, apiKey, hotelID, repository)
+	switch conditional {
+	case "off":
+	case "file":
+		store, err := client.NewFileValidatorStore(conditionalDir)
+		if err != nil {
+			log.Fatalf("failed to create validator store: %v", err)
+		}
+		clientOpts = append(clientOpts, client.WithValidatorStore(store))
+	case "postgres":
+		clientOpts = append(clientOpts, client.WithValidatorStore(database.NewValidatorStore(db)))
 	default:
-		return fmt.Errorf("unknown endpoint type: %s", endpointType)
-	}
-}
-
-func syncHotelContent(ctx context.Context, httpClient *http.Client, baseURL, apiKey string, hotelID int, repository *database.HotelRepository) error {
-	path := fmt.Sprintf("/v3.0/property/%d", hotelID)
-	url := baseURL + path
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("x-api-key", apiKey)
-
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	property, err := client.ParseProperty(body)
-	if err != nil {
-		return fmt.Errorf("failed to parse property: %w", err)
-	}
-
-	if err := repository.StoreProperty(ctx, property); err != nil {
-		return fmt.Errorf("failed to store property: %w", err)
+		log.Fatalf("Invalid conditional mode: %s. Must be one of: off, file, postgres", conditional)
 	}
 
-	return nil
-}
-
-func syncHotelReviews(ctx context.Context, httpClient *http.Client, baseURL, apiKey string, hotelID int, repository *database.HotelRepository) error {
-	reviewCount := 100
-	path := fmt.Sprintf("/v3.0/property/reviews/%d/%d", hotelID, reviewCount)
-	url := baseURL + path
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	if archive != "off" {
+		payloadArchive, err := newPayloadArchive(archive, archiveDir, db)
+		if err != nil {
+			log.Fatal(err)
+		}
+		clientOpts = append(clientOpts, client.WithPayloadArchive(payloadArchive))
 	}
-	req.Header.Set("accept", "application/json")
-	req.Header.Set("x-api-key", apiKey)
 
-	resp, err := httpClient.Do(req)
+	cupidClient, err := client.New(baseURL, clientOpts...)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		log.Fatalf("failed to create Cupid client: %v", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+	repository := database.NewHotelRepository(db)
+	defer connectCacheInvalidation(repository)()
+	runner := &batchRunner{
+		cupidClient: cupidClient,
+		repository:  repository,
+		concurrency: concurrency,
+		policy:      deadLetterPolicy,
+		goneGrace:   goneGrace,
 	}
 
-	if len(body) > 0 {
-		reviews, err := client.ParseReviews(body)
+	singleHotelID := os.Getenv("HOTEL_ID")
+	switch {
+	case singleHotelID != "":
+		hotelID, err := strconv.Atoi(singleHotelID)
 		if err != nil {
-			return fmt.Errorf("failed to parse reviews: %w", err)
+			log.Fatalf("invalid hotel ID: %s", singleHotelID)
 		}
-
-		if len(reviews) > 0 {
-			if err := repository.StoreReviews(ctx, hotelID, reviews); err != nil {
-				return fmt.Errorf("failed to store reviews: %w", err)
-			}
+		p := runner.newPipeline()
+		for _, et := range endpointTypes {
+			p.steps = append(p.steps, &pipelineStep{endpointType: et})
 		}
-	}
-
-	return nil
-}
-
-func syncHotelTranslations(ctx context.Context, httpClient *http.Client, baseURL, apiKey string, hotelID int, repository *database.HotelRepository) error {
-	languages := []string{"fr", "es", "en"}
-	var allTranslations []client.Translation
-
-	for _, lang := range languages {
-		path := fmt.Sprintf("/v3.0/property/%d/lang/%s", hotelID, lang)
-		url := baseURL + path
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			log.Printf("Failed to create request for %s translations for hotel %d: %v", lang, hotelID, err)
-			continue
+		log.Printf("Starting sync for hotel %d", hotelID)
+		if err := p.syncHotel(ctx, hotelID); err != nil {
+			log.Printf("Failed to sync hotel %d: %v", hotelID, err)
 		}
-		req.Header.Set("accept", "application/json")
-		req.Header.Set("x-api-key", apiKey)
-
-		resp, err := httpClient.Do(req)
+		log.Printf("Completed sync for hotel %d", hotelID)
+	case daemonMode:
+		d, err := newDaemon(db, runner, source, schedules, jitter)
 		if err != nil {
-			log.Printf("Failed to get %s translations for hotel %d: %v", lang, hotelID, err)
-			continue
+			log.Fatalf("failed to configure daemon: %v", err)
+		}
+		d.run(ctx)
+	case resumeRunID > 0:
+		if _, err := runner.resume(ctx, resumeRunID); err != nil {
+			log.Fatal(err)
 		}
-		defer resp.Body.Close()
+	default:
+		if _, err := runner.run(ctx, endpointTypes, source); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
 
-		if resp.StatusCode == http.StatusOK {
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				log.Printf("Failed to read response for %s translations for hotel %d: %v", lang, hotelID, err)
-				continue
-			}
+// scheduleFlag sets the cron schedule of an endpoint type from a flag.
+func scheduleFlag(schedules map[EndpointType]string, endpointType EndpointType) func(string) error {
+	return func(expr string) error {
+		schedules[endpointType] = expr
+		return nil
+	}
+}
 
-			if len(body) > 0 {
-				translations, err := client.ParseTranslations(body)
-				if err != nil {
-					log.Printf("Failed to parse %s translations for hotel %d: %v", lang, hotelID, err)
-					continue
-				}
+// connectCacheInvalidation makes every write of repository evict the API
+// server's cached entries of the hotel. Without Redis the writes still go
+// ahead and stale entries expire with their TTL. The returned function closes
+// the connection.
+func connectCacheInvalidation(repository *database.HotelRepository) func() {
+	redisAddr := getEnvOrDefault("REDIS_HOST", "localhost") + ":" + getEnvOrDefault("REDIS_PORT", "6379")
+	redisCache := cache.NewRedisCache(redisAddr)
 
-				for _, translation := range translations {
-					translation.LanguageCode = lang
-					allTranslations = append(allTranslations, translation)
-				}
-			}
-		}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := redisCache.Ping(ctx); err != nil {
+		log.Printf("Warning: Redis connection failed, cached entries will not be invalidated: %v", err)
+		redisCache.Close()
+		return func() {}
 	}
+	repository.SetInvalidator(redisCache)
+	return func() { redisCache.Close() }
+}
 
-	if len(allTranslations) > 0 {
-		if err := repository.StoreTranslations(ctx, hotelID, allTranslations); err != nil {
-			return fmt.Errorf("failed to store translations: %w", err)
-		}
+// connectDB connects to the database configured by the environment.
+func connectDB() (*database.DB, error) {
+	config, err := database.ConfigFromEnv("cupid-data-sync")
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	return database.NewConnection(config)
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
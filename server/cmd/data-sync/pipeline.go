@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+// errContentFailed blocks the steps of a hotel that need its content when the
+// content sync of that hotel failed.
+var errContentFailed = errors.New("content sync failed")
+
+// endpointOrder is the order endpoint types are synced in: reviews and
+// translations reference the hotel written by content.
+var endpointOrder = []EndpointType{ContentEndpoint, ReviewsEndpoint, TranslationsEndpoint}
+
+// parseEndpointTypes parses "all" or a comma separated list of endpoint types
+// and returns them in dependency order.
+func parseEndpointTypes(s string) ([]EndpointType, error) {
+	if strings.TrimSpace(s) == "all" {
+		return endpointOrder, nil
+	}
+
+	selected := make(map[EndpointType]bool)
+	for _, part := range strings.Split(s, ",") {
+		et := EndpointType(strings.TrimSpace(part))
+		switch et {
+		case ContentEndpoint, ReviewsEndpoint, TranslationsEndpoint:
+			selected[et] = true
+		default:
+			return nil, fmt.Errorf("invalid endpoint type: %s. Must be one of: content, reviews, translations, all", et)
+		}
+	}
+
+	var endpointTypes []EndpointType
+	for _, et := range endpointOrder {
+		if selected[et] {
+			endpointTypes = append(endpointTypes, et)
+		}
+	}
+	return endpointTypes, nil
+}
+
+// pipelineStep is one endpoint type of a pipeline.
+type pipelineStep struct {
+	endpointType EndpointType
+	// hotels limits the step to these hotels; nil runs it for every hotel.
+	hotels map[int]bool
+	// record, when set, receives the outcome of the step for every hotel.
+	record func(hotelResult)
+}
+
+// pipeline syncs several endpoint types of a hotel one after the other, so a
+// worker takes a hotel through all its steps.
+type pipeline struct {
+	syncer *syncer
+	steps  []*pipelineStep
+}
+
+// syncHotel runs the steps of a hotel in order. Once its content fails, the
+// remaining steps are blocked instead of attempted. Budget exhaustion and
+// cancellation stop the hotel, leaving its remaining steps pending.
+func (p *pipeline) syncHotel(ctx context.Context, hotelID int) error {
+	contentFailed := false
+	var errs []error
+	for _, step := range p.steps {
+		if step.hotels != nil && !step.hotels[hotelID] {
+			continue
+		}
+
+		start := time.Now()
+		err := errContentFailed
+		if !contentFailed {
+			err = p.syncer.sync(ctx, step.endpointType, hotelID)
+		}
+		if step.record != nil {
+			step.record(hotelResult{HotelID: hotelID, Err: err, Started: start, Duration: time.Since(start)})
+		}
+		if err == nil {
+			continue
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", step.endpointType, err))
+		switch itemStatus(err) {
+		case database.SyncItemSkipped:
+			return errors.Join(errs...)
+		case database.SyncItemFailed:
+			contentFailed = contentFailed || step.endpointType == ContentEndpoint
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+// hotelResult is the outcome of syncing one hotel.
+type hotelResult struct {
+	HotelID  int
+	Err      error
+	Started  time.Time
+	Duration time.Duration
+}
+
+// batchSummary aggregates the results of a batch sync.
+type batchSummary struct {
+	Total      int
+	Successful int
+	Failed     int
+	Skipped    int
+	Elapsed    time.Duration
+	// Stopped is the reason the batch stopped before dispatching every hotel, if any.
+	Stopped error
+}
+
+// collector receives per-hotel results from the workers. It runs on a single
+// goroutine, so it needs no locking.
+type collector struct {
+	total   int
+	summary batchSummary
+	started time.Time
+}
+
+func newCollector(total int) *collector {
+	return &collector{total: total, summary: batchSummary{Total: total}, started: time.Now()}
+}
+
+func (c *collector) add(r hotelResult) {
+	done := c.summary.Successful + c.summary.Failed + 1
+	switch {
+	case errors.Is(r.Err, client.ErrBudgetExhausted), errors.Is(r.Err, context.Canceled):
+		// Not attempted upstream; accounted for as skipped in finish.
+		return
+	case r.Err != nil:
+		c.summary.Failed++
+		log.Printf("Failed to sync hotel %d (%d/%d) in %v: %v", r.HotelID, done, c.total, r.Duration, r.Err)
+	default:
+		c.summary.Successful++
+		log.Printf("Synced hotel %d (%d/%d) in %v", r.HotelID, done, c.total, r.Duration)
+	}
+}
+
+func (c *collector) finish() batchSummary {
+	c.summary.Skipped = c.total - c.summary.Successful - c.summary.Failed
+	c.summary.Elapsed = time.Since(c.started)
+	return c.summary
+}
+
+// runBatch syncs hotelIDs with a pool of concurrency workers and funnels every
+// result to a collector. Dispatch stops as soon as a worker reports that the
+// request budget is exhausted or ctx is cancelled; undispatched hotels are
+// reported as skipped.
+func runBatch(ctx context.Context, hotelIDs []int, concurrency int, syncFn func(context.Context, int) error) batchSummary {
+	parent := ctx
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	results := make(chan hotelResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for hotelID := range jobs {
+				start := time.Now()
+				err := syncFn(ctx, hotelID)
+				results <- hotelResult{HotelID: hotelID, Err: err, Started: start, Duration: time.Since(start)}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, hotelID := range hotelIDs {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- hotelID:
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	c := newCollector(len(hotelIDs))
+	for r := range results {
+		if errors.Is(r.Err, client.ErrBudgetExhausted) && ctx.Err() == nil {
+			log.Printf("Stopping batch sync: %v", r.Err)
+			c.summary.Stopped = r.Err
+			cancel()
+		}
+		c.add(r)
+	}
+	if c.summary.Stopped == nil && parent.Err() != nil {
+		c.summary.Stopped = parent.Err()
+	}
+	return c.finish()
+}
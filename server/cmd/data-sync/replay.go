@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/catalog"
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+const replayUsage = `usage: data-sync replay [flags]
+
+Re-parses archived Cupid responses and stores them again, without calling the
+API. By default only the latest successful response of every URL is replayed;
+endpoint types are replayed in dependency order.`
+
+// newPayloadArchive opens the payload archive selected by the -archive flag.
+func newPayloadArchive(mode, dir string, db *database.DB) (client.PayloadArchive, error) {
+	switch mode {
+	case "file":
+		archive, err := client.NewFileArchive(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create payload archive: %w", err)
+		}
+		return archive, nil
+	case "postgres":
+		return database.NewPayloadArchive(db), nil
+	default:
+		return nil, fmt.Errorf("invalid archive mode: %s. Must be one of: off, file, postgres", mode)
+	}
+}
+
+// apiEndpoints maps the endpoint of an archived URL to its endpoint type.
+var apiEndpoints = map[string]EndpointType{
+	"property":     ContentEndpoint,
+	"reviews":      ReviewsEndpoint,
+	"translations": TranslationsEndpoint,
+}
+
+// runReplayCommand implements the replay subcommand.
+func runReplayCommand(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	archiveMode := fs.String("archive", "file", "Payload archive to replay from: file or postgres")
+	archiveDir := fs.String("archive-dir", ".cache/cupid-payloads", "Directory of the file payload archive")
+	endpoints := fs.String("e", "all", "Endpoint types to replay: content, reviews, translations, a comma separated list, or all")
+	ids := fs.String("ids", "", "Only these comma separated hotel IDs")
+	all := fs.Bool("all", false, "Replay every archived response instead of the latest of every URL")
+	dryRun := fs.Bool("dry-run", false, "Parse without storing")
+	var filter client.PayloadFilter
+	fs.Func("since", "Only responses fetched at or after this time (RFC 3339 or YYYY-MM-DD)", timeFlag(&filter.Since))
+	fs.Func("until", "Only responses fetched before this time (RFC 3339 or YYYY-MM-DD)", timeFlag(&filter.Until))
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), replayUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	filter.SuccessfulOnly = true
+	filter.LatestOnly = !*all
+
+	endpointTypes, err := parseEndpointTypes(*endpoints)
+	if err != nil {
+		return err
+	}
+	hotelIDs, err := catalog.ParseIDs(*ids)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var db *database.DB
+	if !*dryRun || *archiveMode == "postgres" {
+		db, err = connectDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer db.Close()
+	}
+	archive, err := newPayloadArchive(*archiveMode, *archiveDir, db)
+	if err != nil {
+		return err
+	}
+
+	r := &replay{ctx: ctx, hotels: make(map[int]bool, len(hotelIDs))}
+	for _, id := range hotelIDs {
+		r.hotels[id] = true
+	}
+	if !*dryRun {
+		repository := database.NewHotelRepository(db)
+		defer connectCacheInvalidation(repository)()
+		r.repository = repository
+	}
+
+	// One pass per endpoint type, so properties are stored before the
+	// reviews and translations that reference them.
+	for _, et := range endpointTypes {
+		r.endpointType = et
+		if err := archive.WalkPayloads(ctx, filter, r.replayPayload); err != nil {
+			return fmt.Errorf("failed to replay %s payloads: %w", et, err)
+		}
+	}
+
+	verb := "Replayed"
+	if *dryRun {
+		verb = "Parsed"
+	}
+	fmt.Printf("%s %d payloads; %d failed\n", verb, r.replayed, r.failed)
+	if r.failed > 0 {
+		return fmt.Errorf("%d payloads failed to replay", r.failed)
+	}
+	return nil
+}
+
+// replay stores archived payloads of one endpoint type at a time.
+type replay struct {
+	ctx context.Context
+	// repository is nil on dry runs.
+	repository   database.Repository
+	endpointType EndpointType
+	// hotels limits the replay to these hotels; empty replays every hotel.
+	hotels map[int]bool
+
+	replayed, failed int
+}
+
+func (r *replay) replayPayload(p client.RawPayload) error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+
+	path, ok := client.ParseAPIPath(p.URL)
+	if !ok || apiEndpoints[path.Endpoint] != r.endpointType {
+		return nil
+	}
+	if len(r.hotels) > 0 && !r.hotels[path.HotelID] {
+		return nil
+	}
+
+	if err := r.store(path, p.Body); err != nil {
+		log.Printf("%s (fetched %s, request %q): %v", p.URL, p.FetchedAt.Format(time.RFC3339), p.RequestID, err)
+		r.failed++
+		return nil
+	}
+	r.replayed++
+	return nil
+}
+
+// store parses a payload body the way the client does and stores the result.
+func (r *replay) store(path client.APIPath, body []byte) error {
+	switch r.endpointType {
+	case ContentEndpoint:
+		property, err := client.ParseProperty(body)
+		if err != nil {
+			return fmt.Errorf("failed to parse property: %w", err)
+		}
+		if r.repository == nil {
+			return nil
+		}
+		if err := r.repository.StoreProperty(r.ctx, property); err != nil {
+			return fmt.Errorf("failed to store property: %w", err)
+		}
+	case ReviewsEndpoint:
+		if len(body) == 0 {
+			return nil
+		}
+		reviews, err := client.ParseReviews(body)
+		if err != nil {
+			return fmt.Errorf("failed to parse reviews: %w", err)
+		}
+		if r.repository == nil || len(reviews) == 0 {
+			return nil
+		}
+		if err := r.repository.StoreReviews(r.ctx, path.HotelID, reviews); err != nil {
+			return fmt.Errorf("failed to store reviews: %w", err)
+		}
+	case TranslationsEndpoint:
+		if len(body) == 0 {
+			return nil
+		}
+		translations, err := client.ParseTranslations(body)
+		if err != nil {
+			return fmt.Errorf("failed to parse translations: %w", err)
+		}
+		for i := range translations {
+			translations[i].LanguageCode = path.Lang
+		}
+		if r.repository == nil {
+			return nil
+		}
+		if err := r.repository.StoreTranslations(r.ctx, path.HotelID, translations); err != nil {
+			return fmt.Errorf("failed to store translations: %w", err)
+		}
+	}
+	return nil
+}
+
+// timeFlag parses an RFC 3339 timestamp or a date into t.
+func timeFlag(t *time.Time) func(string) error {
+	return func(s string) error {
+		for _, layout := range []string{time.RFC3339, time.DateOnly} {
+			if parsed, err := time.Parse(layout, s); err == nil {
+				*t = parsed
+				return nil
+			}
+		}
+		return errors.New("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+// runRecorder stores the outcome of every hotel of a batch in sync_run_items
+// and keeps the dead-letter queue up to date: failed hotels are dead-lettered,
+// hotels that sync again are removed from it.
+type runRecorder struct {
+	ctx          context.Context
+	repository   *database.HotelRepository
+	runID        int64
+	endpointType EndpointType
+	policy       database.DeadLetterPolicy
+}
+
+// newRunRecorder records results for runID. Writes are not cancelled with
+// ctx, so hotels skipped on shutdown are still recorded.
+func newRunRecorder(ctx context.Context, repository *database.HotelRepository, runID int64, endpointType EndpointType, policy database.DeadLetterPolicy) *runRecorder {
+	return &runRecorder{
+		ctx:          context.WithoutCancel(ctx),
+		repository:   repository,
+		runID:        runID,
+		endpointType: endpointType,
+		policy:       policy,
+	}
+}
+
+func (r *runRecorder) record(result hotelResult) {
+	started := result.Started
+	finished := started.Add(result.Duration)
+	item := database.SyncRunItem{
+		RunID:      r.runID,
+		HotelID:    result.HotelID,
+		Status:     itemStatus(result.Err),
+		DurationMs: result.Duration.Milliseconds(),
+		StartedAt:  &started,
+		FinishedAt: &finished,
+	}
+	if result.Err != nil {
+		item.ErrorText = result.Err.Error()
+		var apiErr *client.Error
+		if errors.As(result.Err, &apiErr) {
+			item.HTTPStatus = apiErr.StatusCode
+		}
+	}
+
+	if err := r.repository.RecordSyncRunItem(r.ctx, item); err != nil {
+		log.Printf("Warning: Failed to record hotel %d in sync run %d: %v", result.HotelID, r.runID, err)
+	}
+
+	switch {
+	case item.Status == database.SyncItemSucceeded, errors.Is(result.Err, errHotelGone):
+		// Hotels gone upstream are tracked by their grace period, not retried with backoff.
+		if err := r.repository.ClearDeadLetter(r.ctx, result.HotelID, string(r.endpointType)); err != nil {
+			log.Printf("Warning: Failed to clear dead letter of hotel %d: %v", result.HotelID, err)
+		}
+	case item.Status == database.SyncItemFailed:
+		letter, err := r.repository.RecordSyncFailure(r.ctx, database.DeadLetter{
+			HotelID:      result.HotelID,
+			EndpointType: string(r.endpointType),
+			ErrorClass:   errorClass(result.Err),
+			LastError:    item.ErrorText,
+			HTTPStatus:   item.HTTPStatus,
+		}, r.policy)
+		if err != nil {
+			log.Printf("Warning: Failed to dead-letter hotel %d: %v", result.HotelID, err)
+			return
+		}
+		if letter.Status == database.DeadLetterParked {
+			log.Printf("Hotel %d parked after %d failed attempts (%s)", result.HotelID, letter.Attempts, letter.ErrorClass)
+		}
+	}
+}
+
+// itemStatus maps a sync error to a sync_run_items status. Hotels that were
+// not attempted upstream are skipped so a resumed run picks them up again.
+func itemStatus(err error) string {
+	switch {
+	case err == nil:
+		return database.SyncItemSucceeded
+	case errors.Is(err, errContentFailed):
+		return database.SyncItemBlocked
+	case errors.Is(err, client.ErrBudgetExhausted), errors.Is(err, context.Canceled):
+		return database.SyncItemSkipped
+	default:
+		return database.SyncItemFailed
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+// hotelSyncTimeout bounds the time spent syncing one endpoint type of a hotel, rate limiter waits included.
+const hotelSyncTimeout = 15 * time.Second
+
+// storeError marks failures to persist fetched data, as opposed to upstream failures.
+type storeError struct {
+	err error
+}
+
+func (e *storeError) Error() string { return e.err.Error() }
+func (e *storeError) Unwrap() error { return e.err }
+
+// errHotelGone marks content syncs of hotels the Cupid API no longer knows.
+var errHotelGone = errors.New("hotel removed upstream")
+
+// syncer syncs single hotels. It is shared by all workers, so the Cupid client,
+// its rate limiter and the database pool are created once per process.
+type syncer struct {
+	cupidClient *client.Client
+	repository  *database.HotelRepository
+	// goneGrace is how long a hotel must keep returning 404 before it is soft-deleted.
+	goneGrace time.Duration
+}
+
+// sync syncs one endpoint type of a hotel.
+func (s *syncer) sync(ctx context.Context, endpointType EndpointType, hotelID int) error {
+	ctx, cancel := context.WithTimeout(ctx, hotelSyncTimeout)
+	defer cancel()
+
+	switch endpointType {
+	case ContentEndpoint:
+		return syncHotelContent(ctx, s.cupidClient, hotelID, s.repository, s.goneGrace)
+	case ReviewsEndpoint:
+		return syncHotelReviews(ctx, s.cupidClient, hotelID, s.repository)
+	case TranslationsEndpoint:
+		return syncHotelTranslations(ctx, s.cupidClient, hotelID, s.repository)
+	default:
+		return fmt.Errorf("unknown endpoint type: %s", endpointType)
+	}
+}
+
+func syncHotelContent(ctx context.Context, cupidClient *client.Client, hotelID int, repository *database.HotelRepository, goneGrace time.Duration) error {
+	property, err := cupidClient.GetProperty(ctx, hotelID)
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return markHotelGone(ctx, hotelID, repository, goneGrace, err)
+	}
+	if err != nil && !errors.Is(err, client.ErrNotModified) {
+		return fmt.Errorf("failed to get property: %w", err)
+	}
+
+	if restored, err := repository.ClearHotelGone(ctx, hotelID); err != nil {
+		log.Printf("Warning: Failed to clear gone state of hotel %d: %v", hotelID, err)
+	} else if restored {
+		log.Printf("Hotel %d is back upstream, restored", hotelID)
+	}
+
+	if property == nil {
+		log.Printf("Hotel %d content unchanged, skipping store", hotelID)
+		return nil
+	}
+	if err := repository.StoreProperty(ctx, property); err != nil {
+		forgetValidators(ctx, cupidClient, client.PropertyPath(hotelID))
+		return fmt.Errorf("failed to store property: %w", &storeError{err})
+	}
+
+	return nil
+}
+
+// markHotelGone handles a 404 for a hotel's content: the hotel is
+// soft-deleted once it has been missing for goneGrace, so a transient upstream
+// glitch does not take it offline. notFound is the API error.
+func markHotelGone(ctx context.Context, hotelID int, repository *database.HotelRepository, goneGrace time.Duration, notFound error) error {
+	deleted, err := repository.MarkHotelGone(ctx, hotelID, goneGrace)
+	if err != nil {
+		return fmt.Errorf("failed to mark hotel gone: %w", &storeError{err})
+	}
+	if deleted {
+		log.Printf("Hotel %d removed upstream, soft-deleted", hotelID)
+		return fmt.Errorf("%w, soft-deleted: %w", errHotelGone, notFound)
+	}
+	return fmt.Errorf("%w, soft-deleting after %s: %w", errHotelGone, goneGrace, notFound)
+}
+
+func syncHotelReviews(ctx context.Context, cupidClient *client.Client, hotelID int, repository *database.HotelRepository) error {
+	reviewCount := 100
+	reviews, err := cupidClient.GetReviews(ctx, hotelID, reviewCount)
+	if errors.Is(err, client.ErrNotModified) {
+		log.Printf("Hotel %d reviews unchanged, skipping store", hotelID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get reviews: %w", err)
+	}
+
+	if len(reviews) > 0 {
+		if err := repository.StoreReviews(ctx, hotelID, reviews); err != nil {
+			forgetValidators(ctx, cupidClient, client.ReviewsPath(hotelID, reviewCount))
+			return fmt.Errorf("failed to store reviews: %w", &storeError{err})
+		}
+	}
+
+	return nil
+}
+
+// syncHotelTranslations stores the translations of every language that could
+// be fetched, and returns the failures of the others so the hotel is retried.
+func syncHotelTranslations(ctx context.Context, cupidClient *client.Client, hotelID int, repository *database.HotelRepository) error {
+	var allTranslations []client.Translation
+	var fetchedPaths []string
+	var errs []error
+
+	for _, lang := range client.Languages {
+		translations, err := cupidClient.GetTranslations(ctx, hotelID, lang)
+		if errors.Is(err, client.ErrNotModified) {
+			continue
+		}
+		if errors.Is(err, client.ErrBudgetExhausted) {
+			return err
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get %s translations: %w", lang, err))
+			continue
+		}
+		fetchedPaths = append(fetchedPaths, client.TranslationsPath(hotelID, lang))
+		allTranslations = append(allTranslations, translations...)
+	}
+
+	if len(allTranslations) > 0 {
+		if err := repository.StoreTranslations(ctx, hotelID, allTranslations); err != nil {
+			forgetValidators(ctx, cupidClient, fetchedPaths...)
+			errs = append(errs, fmt.Errorf("failed to store translations: %w", &storeError{err}))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// forgetValidators drops the conditional request state of payloads that were
+// fetched but not stored, so the next run downloads them again.
+func forgetValidators(ctx context.Context, cupidClient *client.Client, paths ...string) {
+	for _, path := range paths {
+		if err := cupidClient.ForgetValidators(ctx, path); err != nil {
+			log.Printf("Warning: Failed to forget validators for %s: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+func TestSyncHotelTranslations_FailedLanguagesFailTheHotel(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+	cupidClient, err := client.New(upstream.URL)
+	require.NoError(t, err)
+
+	err = syncHotelTranslations(context.Background(), cupidClient, 7, nil)
+	require.Error(t, err)
+	for _, lang := range client.Languages {
+		assert.ErrorContains(t, err, "failed to get "+lang+" translations")
+	}
+
+	// The hotel is recorded as failed and dead-lettered with the upstream status.
+	var apiErr *client.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, database.SyncItemFailed, itemStatus(err))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/apicache"
+	"github.com/vrnvu/cupid/internal/cache"
+	"github.com/vrnvu/cupid/internal/catalog"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+const warmUsage = `usage: data-sync warm [flags]
+
+Preloads the API server's Redis cache with the detail, reviews and
+translations of the most reviewed hotels, or of -ids. Entries still fresh are
+left alone.`
+
+// runWarmCommand implements the warm subcommand.
+func runWarmCommand(args []string) error {
+	fs := flag.NewFlagSet("warm", flag.ExitOnError)
+	top := fs.Int("top", 100, "Number of hotels to warm, by review count")
+	ids := fs.String("ids", "", "Warm these comma separated hotel IDs instead of the top hotels")
+	rps := fs.Float64("rps", 10, "Hotels loaded per second")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), warmUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *rps <= 0 {
+		return fmt.Errorf("invalid -rps %v: must be positive", *rps)
+	}
+
+	hotelIDs, err := catalog.ParseIDs(*ids)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := connectDB()
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+	repository := database.NewHotelRepository(db)
+
+	redisAddr := getEnvOrDefault("REDIS_HOST", "localhost") + ":" + getEnvOrDefault("REDIS_PORT", "6379")
+	redisCache := cache.NewRedisCache(redisAddr)
+	defer redisCache.Close()
+	if err := redisCache.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	if len(hotelIDs) == 0 {
+		if hotelIDs, err = repository.GetTopHotelIDs(ctx, *top); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	warmed, err := apicache.Warm(ctx, repository, redisCache, hotelIDs, *rps)
+	fmt.Printf("Warmed the cache of %d/%d hotels in %s\n", warmed, len(hotelIDs), time.Since(start).Round(time.Millisecond))
+	return err
+}
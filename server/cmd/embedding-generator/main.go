@@ -2,13 +2,14 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/vrnvu/cupid/internal/ai"
+	"github.com/vrnvu/cupid/internal/catalog"
 	"github.com/vrnvu/cupid/internal/database"
 	"github.com/vrnvu/cupid/internal/telemetry"
 )
@@ -20,13 +21,23 @@ type ReviewData struct {
 }
 
 func main() {
+	var source catalog.Source
+	flag.StringVar(&source.IDs, "ids", "", "Comma separated hotel IDs to process")
+	flag.StringVar(&source.File, "ids-file", "", "CSV or NDJSON file of hotel IDs to process")
+	flag.BoolVar(&source.FromDB, "from-db", false, "Process the active hotels of the hotel_catalog table (no hotels are processed by default)")
+	flag.Parse()
+
+	// Embeddings are paid per review, so the whole catalog is only processed
+	// when asked for.
+	if !source.Selected() {
+		log.Fatal("no hotels selected: use --ids, --ids-file or --from-db")
+	}
+
 	openaiAPIKey, ok := os.LookupEnv("OPENAI_API_KEY")
 	if !ok {
 		log.Fatal("OPENAI_API_KEY environment variable is required")
 	}
 
-	hotelIDList := []int{1641879, 317597, 1202743}
-
 	if os.Getenv("ENABLE_TELEMETRY") == "1" {
 		otelShutdown, err := telemetry.ConfigureOpenTelemetry()
 		if err != nil {
@@ -35,13 +46,9 @@ func main() {
 		defer otelShutdown()
 	}
 
-	dbConfig := database.Config{
-		Host:     getEnvOrDefault("DB_HOST", "localhost"),
-		Port:     getEnvOrDefaultInt("DB_PORT", 5432),
-		User:     getEnvOrDefault("DB_USER", "cupid"),
-		Password: getEnvOrDefault("DB_PASSWORD", "cupid123"),
-		DBName:   getEnvOrDefault("DB_NAME", "cupid"),
-		SSLMode:  getEnvOrDefault("DB_SSLMODE", "disable"),
+	dbConfig, err := database.ConfigFromEnv("cupid-embedding-generator")
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	db, err := database.NewConnection(dbConfig)
@@ -55,6 +62,11 @@ func main() {
 
 	ctx := context.Background()
 
+	hotelIDList, err := source.Load(ctx, repository)
+	if err != nil {
+		log.Fatalf("failed to load hotel IDs: %v", err)
+	}
+
 	log.Printf("Processing reviews for hotels: %v", hotelIDList)
 
 	processed := 0
@@ -161,19 +173,3 @@ func markReviewEmbeddingStatus(ctx context.Context, repo *database.HotelReposito
 	_, err := repo.GetDB().ExecContext(ctx, query, status, reviewID)
 	return err
 }
-
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-func getEnvOrDefaultInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
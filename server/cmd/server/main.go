@@ -6,9 +6,11 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/vrnvu/cupid/internal/apicache"
 	"github.com/vrnvu/cupid/internal/cache"
 	"github.com/vrnvu/cupid/internal/database"
 	"github.com/vrnvu/cupid/internal/handlers"
@@ -16,6 +18,13 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if os.Getenv("ENABLE_TELEMETRY") == "1" {
 		otelShutdown, err := telemetry.ConfigureOpenTelemetry()
 		if err != nil {
@@ -24,40 +33,81 @@ func main() {
 		defer otelShutdown()
 	}
 
-	dbConfig := database.Config{
-		Host:     getEnvOrDefault("DB_HOST", "localhost"),
-		Port:     5432,
-		User:     getEnvOrDefault("DB_USER", "cupid"),
-		Password: getEnvOrDefault("DB_PASSWORD", "cupid123"),
-		DBName:   getEnvOrDefault("DB_NAME", "cupid"),
-		SSLMode:  getEnvOrDefault("DB_SSLMODE", "disable"),
-	}
-
-	db, err := database.NewConnection(dbConfig)
+	db, err := connectDB()
 	if err != nil {
 		log.Fatalf("failed to connect to database: %v", err)
 	}
 	defer db.Close()
 
+	if os.Getenv("SKIP_SCHEMA_CHECK") != "1" {
+		if err := checkSchema(db); err != nil {
+			log.Fatalf("%v; run `server migrate up` first or set SKIP_SCHEMA_CHECK=1", err)
+		}
+	}
+
 	repository := database.NewHotelRepository(db)
 
+	localCacheSize, err := strconv.ParseInt(getEnvOrDefault("CACHE_LOCAL_SIZE_MB", "64"), 10, 64)
+	if err != nil {
+		log.Fatalf("invalid CACHE_LOCAL_SIZE_MB: %v", err)
+	}
+	localCacheTTL, err := time.ParseDuration(getEnvOrDefault("CACHE_LOCAL_TTL", "30s"))
+	if err != nil {
+		log.Fatalf("invalid CACHE_LOCAL_TTL: %v", err)
+	}
+
 	redisAddr := getEnvOrDefault("REDIS_HOST", "localhost") + ":" + getEnvOrDefault("REDIS_PORT", "6379")
 	redisCache := cache.NewRedisCache(redisAddr)
 	defer redisCache.Close()
 
+	// backgroundCtx is cancelled on shutdown.
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	closeReplicas, err := setupReplicas(backgroundCtx, repository)
+	if err != nil {
+		log.Fatalf("failed to set up database replicas: %v", err)
+	}
+	defer closeReplicas()
+
+	// Without Redis, the in-process cache keeps entries for their full TTL;
+	// with it, only for CACHE_LOCAL_TTL, since other servers may change them.
+	var store cache.Store
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := redisCache.Ping(ctx); err != nil {
 		log.Printf("Warning: Redis connection failed: %v", err)
-		log.Println("Continuing without cache...")
-		redisCache = nil
+		log.Println("Continuing with the in-process cache only...")
+		local := cache.NewMemoryStore(localCacheSize<<20, 0)
+		repository.SetInvalidator(local)
+		store = local
 	} else {
 		log.Println("Redis cache connected successfully")
+		tiered := cache.NewTiered(cache.NewMemoryStore(localCacheSize<<20, localCacheTTL), redisCache)
+		go tiered.Subscribe(backgroundCtx)
+		repository.SetInvalidator(tiered)
+		store = tiered
+	}
+
+	warmTop, err := strconv.Atoi(getEnvOrDefault("CACHE_WARM_TOP", "0"))
+	if err != nil {
+		log.Fatalf("invalid CACHE_WARM_TOP: %v", err)
+	}
+	warmRPS, err := strconv.ParseFloat(getEnvOrDefault("CACHE_WARM_RPS", "20"), 64)
+	if err != nil || warmRPS <= 0 {
+		log.Fatalf("invalid CACHE_WARM_RPS: must be a positive number")
+	}
+	if warmTop > 0 {
+		go warmCache(backgroundCtx, repository, store, warmTop, warmRPS)
 	}
 
 	apiKey := os.Getenv("API_KEY")
+	adminKey := os.Getenv("ADMIN_API_KEY")
+	if adminKey == "" {
+		log.Println("Warning: ADMIN_API_KEY is not set, the admin API is disabled")
+	}
 
-	server := handlers.NewServer(repository, redisCache, apiKey)
+	server := handlers.NewServer(repository, store, apiKey, adminKey)
 
 	port := getEnvOrDefault("PORT", "8080")
 	addr := ":" + port
@@ -93,6 +143,75 @@ func main() {
 	log.Println("Server exited")
 }
 
+// warmCache preloads the caches of the top hotels by review count while the
+// server starts serving.
+func warmCache(ctx context.Context, repository database.Repository, store cache.Store, top int, rps float64) {
+	hotelIDs, err := repository.GetTopHotelIDs(ctx, top)
+	if err != nil {
+		log.Printf("Warning: Failed to list hotels to warm the cache with: %v", err)
+		return
+	}
+	start := time.Now()
+	warmed, err := apicache.Warm(ctx, repository, store, hotelIDs, rps)
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Warning: Cache warming stopped: %v", err)
+	}
+	log.Printf("Warmed the cache of %d/%d hotels in %s", warmed, len(hotelIDs), time.Since(start).Round(time.Millisecond))
+}
+
+// checkSchema fails when the database is missing migrations this binary
+// depends on.
+func checkSchema(db *database.DB) error {
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return migrator.Check(ctx)
+}
+
+// connectDB connects to the database configured by the environment.
+func connectDB() (*database.DB, error) {
+	config, err := database.ConfigFromEnv("cupid-server")
+	if err != nil {
+		return nil, err
+	}
+	return database.NewConnection(config)
+}
+
+// setupReplicas routes the repository's reads to the replicas of
+// DB_REPLICA_URLS, if any, and checks their health and lag until ctx is done.
+func setupReplicas(ctx context.Context, repository *database.HotelRepository) (func(), error) {
+	replicaConfig, err := database.ReplicaConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if len(replicaConfig.URLs) == 0 {
+		return func() {}, nil
+	}
+	config, err := database.ConfigFromEnv("cupid-server")
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := database.OpenReplicas(config, replicaConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reads go to the primary until unhealthy replicas recover.
+	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := replicas.Check(checkCtx); err != nil {
+		log.Printf("Warning: Database replicas are unhealthy, reading from the primary: %v", err)
+	}
+	go replicas.Monitor(ctx, replicaConfig.CheckInterval)
+
+	repository.SetReplicas(replicas)
+	log.Printf("Reading from %d database replicas with a max lag of %s", len(replicaConfig.URLs), replicaConfig.MaxLag)
+	return func() { replicas.Close() }, nil
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+const migrateUsage = `usage: server migrate <command> [flags]
+
+Commands:
+  up                 Apply the pending migrations
+  down [-steps N]    Revert the last N applied migrations (default 1)
+  status             List the migrations and when they were applied
+  baseline VERSION   Record the migrations up to VERSION as applied without
+                     running them, for databases created before migrations
+                     were tracked`
+
+// runMigrateCommand implements the migrate subcommand.
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("migrate "+action, flag.ExitOnError)
+	steps := fs.Int("steps", 1, "Number of migrations to revert")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), migrateUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := connectDB()
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %s\n", migration)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("schema is at version %d\n", migrator.Latest())
+	case "down":
+		if *steps <= 0 {
+			return fmt.Errorf("invalid -steps %d: must be positive", *steps)
+		}
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %s\n", migration)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "MIGRATION\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\n", status.Migration, appliedAt)
+		}
+		return w.Flush()
+	case "baseline":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: server migrate baseline VERSION")
+		}
+		version, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || version <= 0 || version > migrator.Latest() {
+			return fmt.Errorf("invalid version %q: must be between 1 and %d", fs.Arg(0), migrator.Latest())
+		}
+		recorded, err := migrator.Baseline(ctx, version)
+		for _, migration := range recorded {
+			fmt.Printf("recorded %s\n", migration)
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown migrate command %q\n\n%s", action, migrateUsage)
+	}
+	return nil
+}
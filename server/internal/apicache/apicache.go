@@ -0,0 +1,33 @@
+// Package apicache holds the response caches of the API server, shared by the
+// handlers that read them and the commands that warm them.
+package apicache
+
+import (
+	"time"
+
+	"github.com/vrnvu/cupid/internal/cache"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+// Caches are the response caches of the API. They are nil, and miss on every
+// lookup, when the server runs without a cache store.
+type Caches struct {
+	Hotel        *cache.Cache[client.Property]
+	Hotels       *cache.Cache[[]client.Property]
+	Reviews      *cache.Cache[[]client.Review]
+	Translations *cache.Cache[[]client.Translation]
+}
+
+// reviewsLockTTL bounds how long servers wait for another server loading the
+// same reviews, which the busiest hotels are requested for all at once.
+const reviewsLockTTL = 2 * time.Second
+
+// New returns the caches of the API in store.
+func New(store cache.Store) Caches {
+	return Caches{
+		Hotel:        cache.New[client.Property](store, cache.HotelNamespace),
+		Hotels:       cache.New[[]client.Property](store, cache.HotelListNamespace),
+		Reviews:      cache.New(store, cache.ReviewsNamespace, cache.WithLock[[]client.Review](reviewsLockTTL)),
+		Translations: cache.New[[]client.Translation](store, cache.TranslationsNamespace),
+	}
+}
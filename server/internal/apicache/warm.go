@@ -0,0 +1,84 @@
+package apicache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/vrnvu/cupid/internal/cache"
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+	"golang.org/x/time/rate"
+)
+
+// Repository is the part of database.Repository the caches are loaded from.
+type Repository interface {
+	GetHotelByID(ctx context.Context, hotelID int) (*client.Property, error)
+	GetHotelReviews(ctx context.Context, hotelID int) ([]client.Review, error)
+	GetHotelTranslations(ctx context.Context, hotelID int, languageCode string) ([]client.Translation, error)
+}
+
+// Warm preloads the detail, reviews and translations of hotels into the
+// response caches of store, so the first requests after a deploy or a cache
+// flush do not all go to the database. Entries still fresh are left alone.
+// At most rps hotels are loaded a second. It returns the number of hotels
+// warmed; hotels that fail are logged and skipped, removed hotels are skipped
+// silently.
+func Warm(ctx context.Context, repository Repository, store cache.Store, hotelIDs []int, rps float64) (int, error) {
+	if store == nil {
+		return 0, nil
+	}
+	caches := New(store)
+	limiter := rate.NewLimiter(rate.Limit(rps), 1)
+
+	warmed := 0
+	for _, hotelID := range hotelIDs {
+		if err := limiter.Wait(ctx); err != nil {
+			return warmed, err
+		}
+		err := caches.warm(ctx, repository, hotelID)
+		switch {
+		case err == nil:
+			warmed++
+		case ctx.Err() != nil:
+			return warmed, ctx.Err()
+		case errors.Is(err, database.ErrHotelNotFound), errors.Is(err, database.ErrHotelGone):
+			// Removed hotels have nothing to warm.
+		default:
+			log.Printf("Warning: Failed to warm cache of hotel %d: %v", hotelID, err)
+		}
+	}
+	return warmed, nil
+}
+
+// warm loads the cached responses of one hotel the way the handlers do.
+func (c Caches) warm(ctx context.Context, repository Repository, hotelID int) error {
+	_, _, err := c.Hotel.GetOrLoad(ctx, cache.Key(hotelID), func(ctx context.Context) (client.Property, error) {
+		hotel, err := repository.GetHotelByID(ctx, hotelID)
+		if err != nil {
+			return client.Property{}, err
+		}
+		return *hotel, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load hotel: %w", err)
+	}
+
+	_, _, err = c.Reviews.GetOrLoad(ctx, cache.Key(hotelID), func(ctx context.Context) ([]client.Review, error) {
+		return repository.GetHotelReviews(ctx, hotelID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load reviews: %w", err)
+	}
+
+	for _, lang := range client.Languages {
+		_, _, err = c.Translations.GetOrLoad(ctx, cache.Key(hotelID, lang), func(ctx context.Context) ([]client.Translation, error) {
+			return repository.GetHotelTranslations(ctx, hotelID, lang)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load %s translations: %w", lang, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package apicache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/cache"
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+// stubRepository serves hotel 1, reports hotel 2 as gone and fails on the
+// others. It counts the loads of every hotel.
+type stubRepository struct {
+	mu    sync.Mutex
+	loads map[int]int
+}
+
+func (s *stubRepository) load(hotelID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loads == nil {
+		s.loads = make(map[int]int)
+	}
+	s.loads[hotelID]++
+	switch hotelID {
+	case 1:
+		return nil
+	case 2:
+		return database.ErrHotelGone
+	default:
+		return database.ErrDatabaseConnection
+	}
+}
+
+func (s *stubRepository) GetHotelByID(_ context.Context, hotelID int) (*client.Property, error) {
+	if err := s.load(hotelID); err != nil {
+		return nil, err
+	}
+	return &client.Property{HotelID: hotelID, HotelName: "Warm"}, nil
+}
+
+func (s *stubRepository) GetHotelReviews(_ context.Context, hotelID int) ([]client.Review, error) {
+	return []client.Review{{ID: 10}}, s.load(hotelID)
+}
+
+func (s *stubRepository) GetHotelTranslations(_ context.Context, hotelID int, _ string) ([]client.Translation, error) {
+	return []client.Translation{}, s.load(hotelID)
+}
+
+func TestWarm(t *testing.T) {
+	t.Parallel()
+
+	repo := &stubRepository{}
+	store := cache.NewMemoryStore(1<<20, 0)
+	ctx := context.Background()
+
+	warmed, err := Warm(ctx, repo, store, []int{1, 2, 3}, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, 1, warmed)
+	for _, key := range []string{"hotel:1", "reviews:hotel:1", "translations:hotel:1:fr", "translations:hotel:1:es", "translations:hotel:1:en"} {
+		_, ok, _ := store.Get(ctx, key)
+		assert.True(t, ok, key)
+	}
+	_, ok, _ := store.Get(ctx, "hotel:2")
+	assert.False(t, ok)
+	assert.Equal(t, 2+len(client.Languages), repo.loads[1])
+
+	// Fresh entries are not loaded again.
+	warmed, err = Warm(ctx, repo, store, []int{1}, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, 1, warmed)
+	assert.Equal(t, 2+len(client.Languages), repo.loads[1])
+
+	// The warmed values are those the handlers read.
+	hotel, ok, err := New(store).Hotel.Get(ctx, cache.Key(1))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "Warm", hotel.HotelName)
+}
+
+func TestWarm_Canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	warmed, err := Warm(ctx, &stubRepository{}, cache.NewMemoryStore(1<<20, time.Minute), []int{1}, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, warmed)
+
+	warmed, err = Warm(context.Background(), &stubRepository{}, nil, []int{1}, 1)
+	require.NoError(t, err)
+	assert.Zero(t, warmed)
+}
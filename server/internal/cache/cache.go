@@ -0,0 +1,435 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Store is the byte-level backend of typed caches.
+type Store interface {
+	// Get returns the value of key; ok is false on a miss.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// AddToIndex records key in the set index, which lives for at least ttl.
+	AddToIndex(ctx context.Context, index, key string, ttl time.Duration) error
+	// DeleteIndex deletes index and every key recorded in it.
+	DeleteIndex(ctx context.Context, index string) error
+}
+
+// Namespace is a kind of cached value: its keys share a prefix and a TTL.
+type Namespace struct {
+	Name string
+	TTL  time.Duration
+	// StaleTTL is how long past its TTL GetOrLoad still serves an entry,
+	// while it is refreshed in the background.
+	StaleTTL time.Duration
+	// Version is the schema version of the cached type. Bump it when the type
+	// changes incompatibly: entries of other versions are misses.
+	Version uint16
+	// CompressAbove, when set, gzips encoded values larger than this many bytes.
+	CompressAbove int
+	// Indexed namespaces record their keys, so that all of them can be
+	// invalidated at once. Used for keys not derived from one hotel, such as
+	// list pages.
+	Indexed bool
+}
+
+// compressAbove is the size above which list responses are compressed.
+const compressAbove = 1 << 10
+
+// Namespaces of the API server. Bump the Version of a namespace when its cached
+// type changes, so that servers ignore the entries of other releases.
+var (
+	HotelNamespace        = Namespace{Name: "hotel", TTL: 10 * time.Minute, Version: 1}
+	HotelListNamespace    = Namespace{Name: "hotels:page", TTL: time.Minute, Version: 1, CompressAbove: compressAbove, Indexed: true}
+	ReviewsNamespace      = Namespace{Name: "reviews:hotel", TTL: 5 * time.Minute, StaleTTL: time.Minute, Version: 1, CompressAbove: compressAbove}
+	TranslationsNamespace = Namespace{Name: "translations:hotel", TTL: time.Hour, Version: 1}
+)
+
+// Key joins key parts with colons, e.g. Key(42, "fr") is "42:fr".
+func Key(parts ...any) string {
+	s := make([]string, len(parts))
+	for i, part := range parts {
+		s[i] = fmt.Sprint(part)
+	}
+	return strings.Join(s, ":")
+}
+
+// Key returns the full key of key in the namespace.
+func (ns Namespace) Key(key string) string {
+	return ns.Name + ":" + key
+}
+
+// index is the key of the set recording the keys of an indexed namespace.
+func (ns Namespace) index() string {
+	return ns.Name + ":_index"
+}
+
+// indexPrefix returns the key prefix of the namespace of an index.
+func indexPrefix(index string) string {
+	return strings.TrimSuffix(index, "_index")
+}
+
+// Codec serialises cached values.
+type Codec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// JSONCodec serialises values as JSON.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// Locker is implemented by stores that can coordinate loads across processes.
+type Locker interface {
+	// TryLock takes the lock named key for ttl. ok is false when another
+	// process holds it.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), ok bool, err error)
+}
+
+// InvalidationTracker is implemented by stores that record the invalidations
+// of their keys, so that a load started before an invalidation does not cache
+// the value it read, and a load started soon after one reads data that has it.
+type InvalidationTracker interface {
+	// LastInvalidation returns a generation that changes whenever key is
+	// deleted, and the time of the last deletion.
+	LastInvalidation(key string) (generation uint64, at time.Time)
+}
+
+// loadTimeout bounds the loads of GetOrLoad, which are not cancelled with the
+// request that started them since other requests may be waiting for them.
+const loadTimeout = 10 * time.Second
+
+// Cache caches values of type T in a namespace of a Store.
+type Cache[T any] struct {
+	store Store
+	ns    Namespace
+	codec Codec[T]
+	// lockTTL, when set, makes loads take a store lock so that one process
+	// loads a key while the others wait for its result.
+	lockTTL time.Duration
+	flight  flight[T]
+	now     func() time.Time
+}
+
+// Option configures a Cache.
+type Option[T any] func(*Cache[T])
+
+// WithCodec replaces the default JSON codec.
+func WithCodec[T any](codec Codec[T]) Option[T] {
+	return func(c *Cache[T]) { c.codec = codec }
+}
+
+// WithTTL overrides the TTL of the namespace.
+func WithTTL[T any](ttl time.Duration) Option[T] {
+	return func(c *Cache[T]) { c.ns.TTL = ttl }
+}
+
+// WithLock coalesces the loads of GetOrLoad across processes with a lock held
+// for at most ttl, when the store is a Locker.
+func WithLock[T any](ttl time.Duration) Option[T] {
+	return func(c *Cache[T]) { c.lockTTL = ttl }
+}
+
+// New returns a cache of ns in store. A nil store yields a nil cache, which
+// misses on every Get and ignores writes, so callers need no checks when
+// caching is disabled.
+func New[T any](store Store, ns Namespace, opts ...Option[T]) *Cache[T] {
+	if store == nil {
+		return nil
+	}
+	c := &Cache[T]{store: store, ns: ns, codec: JSONCodec[T]{}, now: time.Now}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Get returns the cached value of key, stale or not; ok is false on a miss.
+// Values that fail to decode are reported as errors.
+func (c *Cache[T]) Get(ctx context.Context, key string) (value T, ok bool, err error) {
+	if c == nil {
+		return value, false, nil
+	}
+	value, _, ok, err = c.get(ctx, key)
+	return value, ok, err
+}
+
+// get returns the cached value of key and the time it is fresh until, and
+// reports the lookup.
+func (c *Cache[T]) get(ctx context.Context, key string) (value T, freshUntil time.Time, ok bool, err error) {
+	ctx, span := c.startSpan(ctx, "cache.get", key)
+	defer func() { endSpan(span, err) }()
+	defer metrics.recordDuration(c.ns.Name, "get", time.Now())
+
+	value, freshUntil, ok, err = c.read(ctx, key)
+	result := resultMiss
+	switch {
+	case err != nil:
+		result = resultError
+	case ok && c.now().After(freshUntil):
+		result = resultStale
+	case ok:
+		result = resultHit
+	}
+	metrics.recordLookup(c.ns.Name, result)
+	span.SetAttributes(attribute.String("cache.result", result))
+	return value, freshUntil, ok, err
+}
+
+// read returns the cached value of key and the time it is fresh until.
+func (c *Cache[T]) read(ctx context.Context, key string) (value T, freshUntil time.Time, ok bool, err error) {
+	data, ok, err := c.store.Get(ctx, c.ns.Key(key))
+	if err != nil || !ok {
+		return value, freshUntil, false, err
+	}
+	metrics.recordPayload(c.ns.Name, "get", len(data))
+	freshUntil, data, err = decodeEntry(c.ns, data)
+	if errors.Is(err, errVersionMismatch) {
+		// Written by a server with another cached type, or envelope format.
+		metrics.recordVersionMismatch(c.ns.Name)
+		return value, freshUntil, false, nil
+	}
+	if err == nil {
+		value, err = c.codec.Unmarshal(data)
+	}
+	if err != nil {
+		metrics.recordSerializationError(c.ns.Name, "decode")
+		return value, freshUntil, false, fmt.Errorf("failed to decode %s: %w", c.ns.Key(key), err)
+	}
+	return value, freshUntil, true, nil
+}
+
+// startSpan starts a span of an operation on key.
+func (c *Cache[T]) startSpan(ctx context.Context, name, key string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(
+		attribute.String("cache.namespace", c.ns.Name),
+		attribute.String("cache.key", key),
+	))
+}
+
+// GetOrLoad returns the cached value of key, or loads and caches it. Loads of
+// a key are coalesced: concurrent misses in this process share one load, and
+// with WithLock so do misses of other processes. An entry past its TTL but
+// within the StaleTTL of the namespace is returned while one background load
+// refreshes it. fromCache reports whether the value came from the cache.
+// Cache failures are logged and fall back to load; load errors are not cached.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, load func(context.Context) (T, error)) (value T, fromCache bool, err error) {
+	if c == nil {
+		value, err = load(ctx)
+		return value, false, err
+	}
+
+	value, freshUntil, ok, err := c.get(ctx, key)
+	if err != nil {
+		log.Printf("Warning: Failed to read cache: %v", err)
+	} else if ok {
+		if c.now().After(freshUntil) {
+			c.flight.do(key, func() (T, error) { return c.load(ctx, key, load) })
+		}
+		return value, true, nil
+	}
+
+	call := c.flight.do(key, func() (T, error) { return c.load(ctx, key, load) })
+	select {
+	case <-call.done:
+		return call.value, false, call.err
+	case <-ctx.Done():
+		var zero T
+		return zero, false, ctx.Err()
+	}
+}
+
+// invalidatedAtKey holds the last invalidation of the key loaded by a
+// GetOrLoad load in its context.
+type invalidatedAtKey struct{}
+
+// InvalidatedAt returns, in the context of a GetOrLoad load, when the loaded
+// key was last invalidated; ok is false outside loads. The value is cached, so
+// it must not be read from data older than that, such as a replica lagging
+// behind the write. With a store that does not track invalidations, the key
+// counts as invalidated when the load started.
+func InvalidatedAt(ctx context.Context) (at time.Time, ok bool) {
+	at, ok = ctx.Value(invalidatedAtKey{}).(time.Time)
+	return at, ok
+}
+
+// load runs a coalesced load of key and caches its result. It keeps the values
+// of ctx, such as trace spans, but not its cancellation.
+func (c *Cache[T]) load(ctx context.Context, key string, load func(context.Context) (T, error)) (value T, err error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+	defer cancel()
+	ctx, span := c.startSpan(ctx, "cache.load", key)
+	defer func() { endSpan(span, err) }()
+
+	if locker, ok := c.store.(Locker); ok && c.lockTTL > 0 {
+		unlock, acquired, err := locker.TryLock(ctx, c.ns.Key(key)+":lock", c.lockTTL)
+		switch {
+		case err != nil:
+			log.Printf("Warning: Failed to lock %s: %v", c.ns.Key(key), err)
+		case acquired:
+			defer unlock()
+		default:
+			if value, ok := c.await(ctx, key); ok {
+				return value, nil
+			}
+		}
+	}
+
+	generation, invalidatedAt := c.lastInvalidation(key)
+	value, err = load(context.WithValue(ctx, invalidatedAtKey{}, invalidatedAt))
+	metrics.recordLoad(c.ns.Name, err)
+	if err != nil {
+		return value, err
+	}
+	if last, _ := c.lastInvalidation(key); last != generation {
+		// Invalidated while loading: the value may predate the write, so it is
+		// returned to the waiting requests but not cached.
+		return value, nil
+	}
+	if err := c.Set(ctx, key, value); err != nil {
+		log.Printf("Warning: Failed to cache %s: %v", c.ns.Key(key), err)
+	}
+	return value, nil
+}
+
+// lastInvalidation returns the last invalidation of key in the store. When the
+// store does not track invalidations, the generation is 0 and the time now.
+func (c *Cache[T]) lastInvalidation(key string) (uint64, time.Time) {
+	if tracker, ok := c.store.(InvalidationTracker); ok {
+		return tracker.LastInvalidation(c.ns.Key(key))
+	}
+	return 0, time.Now()
+}
+
+// awaitInterval is how often await polls for a value loaded by another process.
+const awaitInterval = 25 * time.Millisecond
+
+// await waits up to the lock TTL for another process to cache a fresh value of key.
+func (c *Cache[T]) await(ctx context.Context, key string) (T, bool) {
+	ctx, cancel := context.WithTimeout(ctx, c.lockTTL)
+	defer cancel()
+	ticker := time.NewTicker(awaitInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, false
+		case <-ticker.C:
+			value, freshUntil, ok, err := c.read(ctx, key)
+			if err == nil && ok && !c.now().After(freshUntil) {
+				return value, true
+			}
+		}
+	}
+}
+
+// Set caches value under key for the TTL of the namespace. The entry is kept
+// for the StaleTTL of the namespace after that.
+func (c *Cache[T]) Set(ctx context.Context, key string, value T) (err error) {
+	if c == nil {
+		return nil
+	}
+	ctx, span := c.startSpan(ctx, "cache.set", key)
+	defer func() { endSpan(span, err) }()
+	defer metrics.recordDuration(c.ns.Name, "set", time.Now())
+
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		metrics.recordSerializationError(c.ns.Name, "encode")
+		return fmt.Errorf("failed to encode %s: %w", c.ns.Key(key), err)
+	}
+	fullKey := c.ns.Key(key)
+	data, err = encodeEntry(c.ns, c.now().Add(c.ns.TTL), data)
+	if err != nil {
+		metrics.recordSerializationError(c.ns.Name, "encode")
+		return fmt.Errorf("failed to encode %s: %w", fullKey, err)
+	}
+	metrics.recordPayload(c.ns.Name, "set", len(data))
+	if err := c.store.Set(ctx, fullKey, data, c.ns.TTL+c.ns.StaleTTL); err != nil {
+		return err
+	}
+	if c.ns.Indexed {
+		return c.store.AddToIndex(ctx, c.ns.index(), fullKey, c.ns.TTL+c.ns.StaleTTL)
+	}
+	return nil
+}
+
+// Delete evicts keys.
+func (c *Cache[T]) Delete(ctx context.Context, keys ...string) (err error) {
+	if c == nil || len(keys) == 0 {
+		return nil
+	}
+	ctx, span := c.startSpan(ctx, "cache.delete", strings.Join(keys, ","))
+	defer func() { endSpan(span, err) }()
+	defer metrics.recordDuration(c.ns.Name, "delete", time.Now())
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = c.ns.Key(key)
+	}
+	return c.store.Delete(ctx, fullKeys...)
+}
+
+// flight coalesces concurrent loads of the same key.
+type flight[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// do starts fn for key unless a call for key is in flight, and returns the
+// call; its done channel is closed once the value is set. A panic of fn is
+// returned as the error of the call.
+func (f *flight[T]) do(key string, fn func() (T, error)) *flightCall[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if call, ok := f.calls[key]; ok {
+		return call
+	}
+	if f.calls == nil {
+		f.calls = make(map[string]*flightCall[T])
+	}
+	call := &flightCall[T]{done: make(chan struct{})}
+	f.calls[key] = call
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				call.err = fmt.Errorf("cache load panicked: %v", r)
+			}
+			f.mu.Lock()
+			delete(f.calls, key)
+			f.mu.Unlock()
+			close(call.done)
+		}()
+		call.value, call.err = fn()
+	}()
+	return call
+}
@@ -0,0 +1,380 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+// memoryStore is an in-memory Store without expiry.
+type memoryStore struct {
+	mu      sync.Mutex
+	items   map[string][]byte
+	ttls    map[string]time.Duration
+	indexes map[string][]string
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{items: map[string][]byte{}, ttls: map[string]time.Duration{}, indexes: map[string][]string{}}
+}
+
+func (m *memoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.items[key]
+	return value, ok, nil
+}
+
+func (m *memoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[key] = value
+	m.ttls[key] = ttl
+	return nil
+}
+
+func (m *memoryStore) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.items, key)
+	}
+	return nil
+}
+
+func (m *memoryStore) AddToIndex(_ context.Context, index, key string, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.indexes[index] = append(m.indexes[index], key)
+	return nil
+}
+
+func (m *memoryStore) DeleteIndex(_ context.Context, index string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range m.indexes[index] {
+		delete(m.items, key)
+	}
+	delete(m.indexes, index)
+	return nil
+}
+
+// lengthCodec stores strings as their length, to check the codec is used.
+type lengthCodec struct{}
+
+func (lengthCodec) Marshal(value string) ([]byte, error) {
+	return []byte(strconv.Itoa(len(value))), nil
+}
+
+func (lengthCodec) Unmarshal(data []byte) (string, error) {
+	if string(data) == "bad" {
+		return "", errors.New("bad length")
+	}
+	return "len=" + string(data), nil
+}
+
+func TestCache_GetSet(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	ctx := context.Background()
+	c := New[client.Property](store, HotelNamespace)
+
+	_, ok, err := c.Get(ctx, Key(1))
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	hotel := client.Property{HotelID: 1, HotelName: "Cached"}
+	require.NoError(t, c.Set(ctx, Key(1), hotel))
+	assert.Contains(t, string(store.items["hotel:1"]), `"hotel_name":"Cached"`)
+	assert.Equal(t, HotelNamespace.TTL, store.ttls["hotel:1"])
+
+	cached, ok, err := c.Get(ctx, Key(1))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, hotel, cached)
+
+	require.NoError(t, c.Delete(ctx, Key(1)))
+	_, ok, err = c.Get(ctx, Key(1))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCache_Options(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	ctx := context.Background()
+	ns := Namespace{Name: "test", TTL: time.Minute, Indexed: true}
+	c := New(store, ns, WithCodec[string](lengthCodec{}), WithTTL[string](time.Hour))
+
+	require.NoError(t, c.Set(ctx, Key("a", 2), "hello"))
+	assert.Equal(t, []byte("5"), store.items["test:a:2"][envelopeHeaderSize:])
+	assert.Equal(t, time.Hour, store.ttls["test:a:2"])
+	assert.Equal(t, []string{"test:a:2"}, store.indexes["test:_index"])
+
+	value, ok, err := c.Get(ctx, Key("a", 2))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "len=5", value)
+
+	bad, err := encodeEntry(ns, time.Now(), []byte("bad"))
+	require.NoError(t, err)
+	for _, data := range [][]byte{bad, {envelopeFormat, 0, 0}} {
+		store.items["test:a:2"] = data
+		_, ok, err = c.Get(ctx, Key("a", 2))
+		assert.ErrorContains(t, err, "failed to decode test:a:2")
+		assert.False(t, ok)
+	}
+}
+
+func TestCache_NilStore(t *testing.T) {
+	t.Parallel()
+
+	c := New[string](nil, HotelNamespace)
+	assert.Nil(t, c)
+
+	ctx := context.Background()
+	require.NoError(t, c.Set(ctx, "k", "v"))
+	require.NoError(t, c.Delete(ctx, "k"))
+	_, ok, err := c.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	ctx := context.Background()
+	c := New[string](store, Namespace{Name: "test", TTL: time.Minute, StaleTTL: time.Minute})
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		loads.Add(1)
+		<-release
+		return "loaded", nil
+	}
+
+	// Concurrent misses share one load.
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, fromCache, err := c.GetOrLoad(ctx, "k", load)
+			assert.NoError(t, err)
+			assert.False(t, fromCache)
+			assert.Equal(t, "loaded", value)
+		}()
+	}
+	require.Eventually(t, func() bool { return loads.Load() == 1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), loads.Load())
+	assert.Equal(t, 2*time.Minute, store.ttls["test:k"])
+
+	value, fromCache, err := c.GetOrLoad(ctx, "k", load)
+	require.NoError(t, err)
+	assert.True(t, fromCache)
+	assert.Equal(t, "loaded", value)
+	assert.Equal(t, int32(1), loads.Load())
+}
+
+func TestCache_GetOrLoad_StaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	ctx := context.Background()
+	c := New[string](store, Namespace{Name: "test", TTL: time.Minute, StaleTTL: time.Minute})
+	require.NoError(t, c.Set(ctx, "k", "old"))
+	c.now = func() time.Time { return time.Now().Add(90 * time.Second) }
+
+	refreshed := make(chan struct{})
+	value, fromCache, err := c.GetOrLoad(ctx, "k", func(context.Context) (string, error) {
+		defer close(refreshed)
+		return "new", nil
+	})
+	require.NoError(t, err)
+	assert.True(t, fromCache)
+	assert.Equal(t, "old", value, "stale value is served while refreshing")
+
+	<-refreshed
+	require.Eventually(t, func() bool {
+		value, _, _ := c.Get(ctx, "k")
+		return value == "new"
+	}, time.Second, time.Millisecond)
+}
+
+func TestCache_GetOrLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	ctx := context.Background()
+	c := New[string](store, HotelNamespace)
+
+	_, _, err := c.GetOrLoad(ctx, "k", func(context.Context) (string, error) {
+		return "", errors.New("load failed")
+	})
+	assert.EqualError(t, err, "load failed")
+	_, ok, _ := c.Get(ctx, "k")
+	assert.False(t, ok, "load errors are not cached")
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err = c.GetOrLoad(canceled, "slow", func(context.Context) (string, error) {
+		time.Sleep(50 * time.Millisecond)
+		return "v", nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var nilCache *Cache[string]
+	value, fromCache, err := nilCache.GetOrLoad(ctx, "k", func(context.Context) (string, error) { return "v", nil })
+	require.NoError(t, err)
+	assert.False(t, fromCache)
+	assert.Equal(t, "v", value)
+}
+
+func TestCache_GetOrLoad_InvalidatedWhileLoading(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore(1<<10, 0)
+	ctx := context.Background()
+	c := New[string](store, HotelNamespace)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan string)
+	go func() {
+		value, _, err := c.GetOrLoad(ctx, "7", func(context.Context) (string, error) {
+			close(started)
+			<-release
+			return "before write", nil
+		})
+		assert.NoError(t, err)
+		done <- value
+	}()
+
+	// A write invalidates the hotel while its old value is being loaded.
+	<-started
+	require.NoError(t, store.Invalidate(ctx, Invalidation{HotelID: 7, Entity: EntityHotel}))
+	close(release)
+	assert.Equal(t, "before write", <-done, "waiters still get the loaded value")
+
+	_, ok, err := c.Get(ctx, "7")
+	require.NoError(t, err)
+	assert.False(t, ok, "a load overtaken by an invalidation is not cached")
+
+	value, _, err := c.GetOrLoad(ctx, "7", func(context.Context) (string, error) { return "after write", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "after write", value)
+	value, ok, err = c.Get(ctx, "7")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "after write", value)
+}
+
+func TestCache_GetOrLoad_Panic(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	ctx := context.Background()
+	c := New[string](store, HotelNamespace)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		loads.Add(1)
+		<-release
+		panic("boom")
+	}
+
+	// Every request waiting on the load gets the panic as an error.
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := c.GetOrLoad(ctx, "k", load)
+			assert.EqualError(t, err, "cache load panicked: boom")
+		}()
+	}
+	require.Eventually(t, func() bool { return loads.Load() == 1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// The failed load is not left in flight.
+	value, _, err := c.GetOrLoad(ctx, "k", func(context.Context) (string, error) { return "v", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "v", value)
+}
+
+func TestCache_GetOrLoad_InvalidatedAt(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, ok := InvalidatedAt(ctx)
+	assert.False(t, ok)
+
+	invalidatedAt := func(c *Cache[string], key string) time.Time {
+		var at time.Time
+		_, _, err := c.GetOrLoad(ctx, key, func(ctx context.Context) (string, error) {
+			var ok bool
+			at, ok = InvalidatedAt(ctx)
+			assert.True(t, ok, "loads carry the last invalidation of their key")
+			return "v", nil
+		})
+		require.NoError(t, err)
+		return at
+	}
+
+	store := NewMemoryStore(1<<10, 0)
+	c := New[string](store, HotelNamespace)
+	created := invalidatedAt(c, "1")
+	invalidated := time.Now()
+	require.NoError(t, store.Invalidate(ctx, Invalidation{HotelID: 2, Entity: EntityHotel}))
+	assert.Equal(t, created, invalidatedAt(c, "3"), "other keys keep the time the store was created")
+	assert.False(t, invalidatedAt(c, "2").Before(invalidated))
+
+	// Without tracking, keys count as invalidated when the load starts.
+	started := time.Now()
+	assert.False(t, invalidatedAt(New[string](newMemoryStore(), HotelNamespace), "1").Before(started))
+}
+
+// lockingStore is a memoryStore whose lock is held by another process.
+type lockingStore struct {
+	*memoryStore
+	locked bool
+}
+
+func (l *lockingStore) TryLock(context.Context, string, time.Duration) (func(), bool, error) {
+	return func() {}, !l.locked, nil
+}
+
+func TestCache_GetOrLoad_Lock(t *testing.T) {
+	t.Parallel()
+
+	store := &lockingStore{memoryStore: newMemoryStore(), locked: true}
+	ctx := context.Background()
+	c := New(store, HotelNamespace, WithLock[string](time.Second))
+
+	// The holder of the lock caches the value while we wait.
+	go func() {
+		time.Sleep(2 * awaitInterval)
+		_ = New[string](store.memoryStore, HotelNamespace).Set(ctx, "k", "theirs")
+	}()
+	value, _, err := c.GetOrLoad(ctx, "k", func(context.Context) (string, error) {
+		return "ours", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "theirs", value)
+}
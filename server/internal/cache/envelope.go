@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/compress"
+)
+
+// Cached values are stored in an envelope: a header followed by the encoded
+// value, compressed or not.
+//
+//	byte  0     envelope format
+//	byte  1     payload encoding
+//	bytes 2-3   schema version of the namespace, big-endian
+//	bytes 4-11  UnixNano time the value is fresh until, big-endian
+const (
+	envelopeFormat     = 1
+	envelopeHeaderSize = 12
+)
+
+// Payload encodings.
+const (
+	encodingRaw  byte = 0
+	encodingGzip byte = 1
+)
+
+// errVersionMismatch reports an entry written with another envelope format or
+// schema version, which readers treat as a miss.
+var errVersionMismatch = errors.New("cache entry version mismatch")
+
+// encodeEntry wraps an encoded value of ns in an envelope.
+func encodeEntry(ns Namespace, freshUntil time.Time, data []byte) ([]byte, error) {
+	encoding := encodingRaw
+	if ns.CompressAbove > 0 && len(data) > ns.CompressAbove {
+		compressed, err := compress.Gzip(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress: %w", err)
+		}
+		data, encoding = compressed, encodingGzip
+	}
+
+	entry := make([]byte, envelopeHeaderSize, envelopeHeaderSize+len(data))
+	entry[0] = envelopeFormat
+	entry[1] = encoding
+	binary.BigEndian.PutUint16(entry[2:4], ns.Version)
+	binary.BigEndian.PutUint64(entry[4:12], uint64(freshUntil.UnixNano()))
+	return append(entry, data...), nil
+}
+
+// decodeEntry unwraps an entry of ns, returning errVersionMismatch for
+// entries of another format or version.
+func decodeEntry(ns Namespace, entry []byte) (time.Time, []byte, error) {
+	if len(entry) > 0 && entry[0] != envelopeFormat {
+		return time.Time{}, nil, errVersionMismatch
+	}
+	if len(entry) < envelopeHeaderSize {
+		return time.Time{}, nil, errors.New("truncated cache entry")
+	}
+	if binary.BigEndian.Uint16(entry[2:4]) != ns.Version {
+		return time.Time{}, nil, errVersionMismatch
+	}
+	freshUntil := time.Unix(0, int64(binary.BigEndian.Uint64(entry[4:12])))
+
+	data := entry[envelopeHeaderSize:]
+	switch entry[1] {
+	case encodingRaw:
+		return freshUntil, data, nil
+	case encodingGzip:
+		data, err := compress.Gunzip(data)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("failed to decompress: %w", err)
+		}
+		return freshUntil, data, nil
+	default:
+		return time.Time{}, nil, fmt.Errorf("unknown cache entry encoding %d", entry[1])
+	}
+}
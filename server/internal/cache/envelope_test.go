@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+func TestEnvelope(t *testing.T) {
+	t.Parallel()
+
+	freshUntil := time.Unix(0, time.Now().UnixNano())
+	small := []byte(`[{"id":1}]`)
+	large := []byte(strings.Repeat(`{"id":1,"content":"Great stay"},`, 100))
+
+	tests := []struct {
+		name     string
+		ns       Namespace
+		data     []byte
+		encoding byte
+	}{
+		{name: "uncompressed namespace", ns: Namespace{Name: "raw"}, data: large, encoding: encodingRaw},
+		{name: "below threshold", ns: Namespace{Name: "gz", CompressAbove: 1024}, data: small, encoding: encodingRaw},
+		{name: "above threshold", ns: Namespace{Name: "gz", CompressAbove: 1024}, data: large, encoding: encodingGzip},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			entry, err := encodeEntry(tc.ns, freshUntil, tc.data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.encoding, entry[1])
+			if tc.encoding == encodingGzip {
+				assert.Less(t, len(entry), len(tc.data))
+			}
+
+			gotFreshUntil, data, err := decodeEntry(tc.ns, entry)
+			require.NoError(t, err)
+			assert.Equal(t, tc.data, data)
+			assert.True(t, freshUntil.Equal(gotFreshUntil))
+		})
+	}
+}
+
+func TestEnvelope_Errors(t *testing.T) {
+	t.Parallel()
+
+	ns := Namespace{Name: "test", Version: 2}
+	entry, err := encodeEntry(ns, time.Now(), []byte("v"))
+	require.NoError(t, err)
+
+	_, _, err = decodeEntry(Namespace{Name: "test", Version: 3}, entry)
+	assert.ErrorIs(t, err, errVersionMismatch)
+
+	// The pre-envelope format started with a timestamp.
+	_, _, err = decodeEntry(ns, []byte{0x18, 0xdf, 0xa8, 0x24, 0xaf, 0x59, 0x52, 0x83, '5'})
+	assert.ErrorIs(t, err, errVersionMismatch)
+
+	_, _, err = decodeEntry(ns, entry[:5])
+	assert.ErrorContains(t, err, "truncated")
+
+	unknown := append([]byte{}, entry...)
+	unknown[1] = 9
+	_, _, err = decodeEntry(ns, unknown)
+	assert.ErrorContains(t, err, "unknown cache entry encoding 9")
+
+	corrupt := append([]byte{}, entry...)
+	corrupt[1] = encodingGzip
+	_, _, err = decodeEntry(ns, corrupt)
+	assert.ErrorContains(t, err, "failed to decompress")
+}
+
+func TestCache_VersionMismatchIsMiss(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	ctx := context.Background()
+	v1 := Namespace{Name: "reviews", TTL: time.Minute, Version: 1, CompressAbove: 64}
+	v2 := v1
+	v2.Version = 2
+
+	reviews := []client.Review{{ID: 1, Content: strings.Repeat("Lovely ", 20)}}
+	require.NoError(t, New[[]client.Review](store, v1).Set(ctx, "1", reviews))
+
+	cached, ok, err := New[[]client.Review](store, v1).Get(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, reviews, cached)
+
+	_, ok, err = New[[]client.Review](store, v2).Get(ctx, "1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestNamespaces_VersionBumpIsMiss(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for _, ns := range []Namespace{HotelNamespace, HotelListNamespace, ReviewsNamespace, TranslationsNamespace} {
+		assert.NotZero(t, ns.Version, ns.Name)
+
+		store := newMemoryStore()
+		require.NoError(t, New[string](store, ns).Set(ctx, "1", "cached"))
+		_, ok, err := New[string](store, ns).Get(ctx, "1")
+		require.NoError(t, err)
+		assert.True(t, ok, ns.Name)
+
+		bumped := ns
+		bumped.Version++
+		_, ok, err = New[string](store, bumped).Get(ctx, "1")
+		require.NoError(t, err)
+		assert.False(t, ok, "%s entries of the previous version are misses", ns.Name)
+	}
+}
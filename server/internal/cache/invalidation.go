@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+// Entities of a hotel whose cached data is invalidated together.
+const (
+	EntityHotel        = "hotel"
+	EntityReviews      = "reviews"
+	EntityTranslations = "translations"
+)
+
+// Invalidation names the data of a hotel that a write changed.
+type Invalidation struct {
+	HotelID int    `json:"hotel_id"`
+	Entity  string `json:"entity"`
+}
+
+// Invalidator evicts cached data made stale by a write.
+type Invalidator interface {
+	Invalidate(ctx context.Context, inv Invalidation) error
+}
+
+// keys returns the cache keys holding data of the invalidated entity.
+func (inv Invalidation) keys() []string {
+	switch inv.Entity {
+	case EntityHotel:
+		return []string{HotelNamespace.Key(Key(inv.HotelID))}
+	case EntityReviews:
+		return []string{ReviewsNamespace.Key(Key(inv.HotelID))}
+	case EntityTranslations:
+		keys := make([]string, len(client.Languages))
+		for i, lang := range client.Languages {
+			keys[i] = TranslationsNamespace.Key(Key(inv.HotelID, lang))
+		}
+		return keys
+	default:
+		return nil
+	}
+}
+
+// indexes returns the indexed namespaces whose keys may include the entity:
+// hotel list pages.
+func (inv Invalidation) indexes() []string {
+	switch inv.Entity {
+	case EntityHotel:
+		return []string{HotelListNamespace.index()}
+	default:
+		return nil
+	}
+}
+
+// Invalidate evicts the cached data of an entity from store.
+func Invalidate(ctx context.Context, store Store, inv Invalidation) error {
+	err := store.Delete(ctx, inv.keys()...)
+	for _, index := range inv.indexes() {
+		err = errors.Join(err, store.DeleteIndex(ctx, index))
+	}
+	return err
+}
+
+// InvalidationChannel is the Redis channel invalidations are published on,
+// for servers to evict them from their in-process tier.
+const InvalidationChannel = "cache:invalidations"
+
+// Invalidate deletes the keys of the invalidated entity, so every server
+// reading this Redis fetches it from the database again, and publishes the
+// invalidation to their in-process tiers.
+func (r *RedisCache) Invalidate(ctx context.Context, inv Invalidation) error {
+	err := Invalidate(ctx, r, inv)
+	msg, marshalErr := json.Marshal(inv)
+	if marshalErr != nil {
+		return errors.Join(err, marshalErr)
+	}
+	if pubErr := r.client.Publish(ctx, InvalidationChannel, msg).Err(); pubErr != nil {
+		err = errors.Join(err, fmt.Errorf("redis publish error: %w", pubErr))
+	}
+	return err
+}
+
+// SubscribeInvalidations implements Broadcaster. Invalidations published
+// while the subscription reconnects are missed; the TTL of the in-process
+// tier bounds how long they stay stale.
+func (r *RedisCache) SubscribeInvalidations(ctx context.Context, fn func(Invalidation)) error {
+	sub := r.client.Subscribe(ctx, InvalidationChannel)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("redis subscribe error: %w", err)
+	}
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return errors.New("redis subscription closed")
+			}
+			var inv Invalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+				log.Printf("Warning: Invalid cache invalidation %q: %v", msg.Payload, err)
+				continue
+			}
+			fn(inv)
+		}
+	}
+}
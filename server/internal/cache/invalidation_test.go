@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidation_Keys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		inv     Invalidation
+		keys    []string
+		indexes []string
+	}{
+		{"hotel", Invalidation{HotelID: 42, Entity: EntityHotel}, []string{"hotel:42"}, []string{"hotels:page:_index"}},
+		{"reviews", Invalidation{HotelID: 42, Entity: EntityReviews}, []string{"reviews:hotel:42"}, nil},
+		{
+			"translations",
+			Invalidation{HotelID: 42, Entity: EntityTranslations},
+			[]string{"translations:hotel:42:fr", "translations:hotel:42:es", "translations:hotel:42:en"},
+			nil,
+		},
+		{"unknown", Invalidation{HotelID: 42, Entity: "rooms"}, nil, nil},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.keys, tc.inv.keys())
+			assert.Equal(t, tc.indexes, tc.inv.indexes())
+		})
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	ctx := context.Background()
+	hotels := New[[]string](store, HotelListNamespace)
+	hotel := New[string](store, HotelNamespace)
+	require.NoError(t, hotels.Set(ctx, Key(50, 0), []string{"a", "b"}))
+	require.NoError(t, hotels.Set(ctx, Key(50, 50), []string{"c"}))
+	require.NoError(t, hotel.Set(ctx, Key(42), "a"))
+	require.NoError(t, hotel.Set(ctx, Key(43), "b"))
+
+	require.NoError(t, Invalidate(ctx, store, Invalidation{HotelID: 42, Entity: EntityHotel}))
+
+	_, ok, err := hotel.Get(ctx, Key(42))
+	require.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = hotel.Get(ctx, Key(43))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	for _, key := range []string{Key(50, 0), Key(50, 50)} {
+		_, ok, err = hotels.Get(ctx, key)
+		require.NoError(t, err)
+		assert.False(t, ok, key)
+	}
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-process Store that evicts the least recently used
+// entries once its values exceed a size in bytes.
+type MemoryStore struct {
+	maxBytes int64
+	maxTTL   time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	bytes int64
+	lru   *list.List
+	items map[string]*list.Element
+
+	// generation counts deletions. deleted and deletedPrefixes hold the last
+	// deletion of keys and of index namespaces; keys not in deleted were last
+	// deleted at floor at the latest.
+	generation      uint64
+	floor           deletion
+	deleted         map[string]deletion
+	deletedPrefixes map[string]deletion
+}
+
+// deletion is a deletion of keys: its generation and when it was made.
+type deletion struct {
+	generation uint64
+	at         time.Time
+}
+
+// maxDeletedKeys bounds the deletions a MemoryStore remembers. Past it they
+// are forgotten at once, which invalidates the loads in flight.
+const maxDeletedKeys = 1 << 16
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryStore returns a store holding up to maxBytes of values. A positive
+// maxTTL caps the TTL of entries, so that they are reloaded from a shared
+// tier soon after other processes change them.
+func NewMemoryStore(maxBytes int64, maxTTL time.Duration) *MemoryStore {
+	return &MemoryStore{
+		maxBytes: maxBytes,
+		maxTTL:   maxTTL,
+		now:      time.Now,
+		lru:      list.New(),
+		items:    make(map[string]*list.Element),
+
+		// Writes made before the store existed were not seen, so every key
+		// counts as deleted when it was created.
+		floor:           deletion{at: time.Now()},
+		deleted:         make(map[string]deletion),
+		deletedPrefixes: make(map[string]deletion),
+	}
+}
+
+func (m *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	elem, ok := m.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if m.now().After(entry.expires) {
+		m.remove(elem)
+		return nil, false, nil
+	}
+	m.lru.MoveToFront(elem)
+	return entry.value, true, nil
+}
+
+func (m *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	if m.maxTTL > 0 && ttl > m.maxTTL {
+		ttl = m.maxTTL
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if elem, ok := m.items[key]; ok {
+		m.remove(elem)
+	}
+	if int64(len(value)) > m.maxBytes {
+		return nil
+	}
+
+	m.items[key] = m.lru.PushFront(&memoryEntry{key: key, value: value, expires: m.now().Add(ttl)})
+	m.bytes += int64(len(value))
+	for m.bytes > m.maxBytes {
+		m.remove(m.lru.Back())
+	}
+	return nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.generation++
+	deleted := deletion{generation: m.generation, at: time.Now()}
+	if len(m.deleted)+len(keys) > maxDeletedKeys {
+		clear(m.deleted)
+		m.floor = deleted
+	}
+	for _, key := range keys {
+		if elem, ok := m.items[key]; ok {
+			m.remove(elem)
+		}
+		m.deleted[key] = deleted
+	}
+	return nil
+}
+
+// AddToIndex implements Store. It records nothing: DeleteIndex evicts every
+// key of the namespace instead, including keys copied from another tier
+// without their index.
+func (m *MemoryStore) AddToIndex(context.Context, string, string, time.Duration) error {
+	return nil
+}
+
+// DeleteIndex implements Store by deleting every key of the namespace of index.
+func (m *MemoryStore) DeleteIndex(_ context.Context, index string) error {
+	prefix := indexPrefix(index)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.generation++
+	m.deletedPrefixes[prefix] = deletion{generation: m.generation, at: time.Now()}
+	for key, elem := range m.items {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(elem)
+		}
+	}
+	return nil
+}
+
+// LastInvalidation implements InvalidationTracker. It covers the invalidations
+// of other processes received by a Tiered store, which are deleted here too.
+func (m *MemoryStore) LastInvalidation(key string) (uint64, time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	last := m.floor
+	if deleted, ok := m.deleted[key]; ok && deleted.generation > last.generation {
+		last = deleted
+	}
+	for prefix, deleted := range m.deletedPrefixes {
+		if strings.HasPrefix(key, prefix) && deleted.generation > last.generation {
+			last = deleted
+		}
+	}
+	return last.generation, last.at
+}
+
+// Invalidate evicts the cached data of an entity. With no shared tier, this
+// is all a server needs to see its own writes.
+func (m *MemoryStore) Invalidate(ctx context.Context, inv Invalidation) error {
+	return Invalidate(ctx, m, inv)
+}
+
+// Len returns the number of entries, including expired ones not yet evicted.
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
+
+func (m *MemoryStore) remove(elem *list.Element) {
+	entry := m.lru.Remove(elem).(*memoryEntry)
+	delete(m.items, entry.key)
+	m.bytes -= int64(len(entry.value))
+}
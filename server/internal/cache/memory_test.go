@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := NewMemoryStore(10, 0)
+
+	require.NoError(t, m.Set(ctx, "a", []byte("aaaa"), time.Minute))
+	require.NoError(t, m.Set(ctx, "b", []byte("bbbb"), time.Minute))
+	_, ok, _ := m.Get(ctx, "a")
+	require.True(t, ok)
+
+	// c does not fit with a and b; b was used least recently.
+	require.NoError(t, m.Set(ctx, "c", []byte("cccc"), time.Minute))
+	_, ok, _ = m.Get(ctx, "b")
+	assert.False(t, ok)
+	_, ok, _ = m.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, m.Len())
+
+	// Values larger than the store are not kept, nor is what they replace.
+	require.NoError(t, m.Set(ctx, "a", []byte("too large value"), time.Minute))
+	_, ok, _ = m.Get(ctx, "a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, m.Len())
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := NewMemoryStore(1<<10, 30*time.Second)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	require.NoError(t, m.Set(ctx, "short", []byte("v"), 10*time.Second))
+	require.NoError(t, m.Set(ctx, "capped", []byte("v"), time.Hour))
+
+	now = now.Add(20 * time.Second)
+	_, ok, _ := m.Get(ctx, "short")
+	assert.False(t, ok)
+	_, ok, _ = m.Get(ctx, "capped")
+	assert.True(t, ok)
+
+	now = now.Add(20 * time.Second)
+	_, ok, _ = m.Get(ctx, "capped")
+	assert.False(t, ok, "TTL is capped at the max TTL")
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestMemoryStore_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := NewMemoryStore(1<<10, 0)
+	for _, key := range []string{"hotel:7", "hotels:page:10:0", "hotels:page:10:10", "reviews:hotel:7"} {
+		require.NoError(t, m.Set(ctx, key, []byte("v"), time.Minute))
+	}
+
+	// Hotel pages are dropped by namespace, as they carry no local index.
+	require.NoError(t, m.Invalidate(ctx, Invalidation{HotelID: 7, Entity: EntityHotel}))
+	for key, want := range map[string]bool{"hotel:7": false, "hotels:page:10:0": false, "hotels:page:10:10": false, "reviews:hotel:7": true} {
+		_, ok, _ := m.Get(ctx, key)
+		assert.Equal(t, want, ok, key)
+	}
+}
+
+func TestMemoryStore_LastInvalidation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	created := time.Now()
+	m := NewMemoryStore(1<<10, 0)
+
+	hotel, hotelAt := m.LastInvalidation("hotel:7")
+	page, _ := m.LastInvalidation("hotels:page:10:0")
+	reviews, reviewsAt := m.LastInvalidation("reviews:hotel:7")
+	assert.False(t, reviewsAt.Before(created), "keys count as invalidated when the store was created")
+
+	require.NoError(t, m.Invalidate(ctx, Invalidation{HotelID: 7, Entity: EntityHotel}))
+	generation, at := m.LastInvalidation("hotel:7")
+	assert.NotEqual(t, hotel, generation)
+	assert.True(t, at.After(hotelAt))
+	generation, _ = m.LastInvalidation("hotels:page:10:0")
+	assert.NotEqual(t, page, generation, "index deletions count for every key of the namespace")
+	generation, at = m.LastInvalidation("reviews:hotel:7")
+	assert.Equal(t, reviews, generation)
+	assert.Equal(t, reviewsAt, at)
+
+	// Forgetting deletions changes the generation of every key.
+	m.deleted = make(map[string]deletion, maxDeletedKeys)
+	for i := range maxDeletedKeys {
+		m.deleted[strconv.Itoa(i)] = deletion{generation: 1}
+	}
+	require.NoError(t, m.Delete(ctx, "other"))
+	generation, _ = m.LastInvalidation("reviews:hotel:7")
+	assert.NotEqual(t, reviews, generation)
+	assert.Len(t, m.deleted, 1)
+}
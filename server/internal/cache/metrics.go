@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/vrnvu/cupid/internal/cache"
+
+// Results of cache lookups.
+const (
+	resultHit   = "hit"
+	resultStale = "stale"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+// cacheMetrics holds the OpenTelemetry instruments reported by typed caches.
+// Instruments come from the global meter provider, so they are no-ops until
+// telemetry.ConfigureOpenTelemetry has been called.
+type cacheMetrics struct {
+	lookups             metric.Int64Counter
+	duration            metric.Float64Histogram
+	payloadBytes        metric.Int64Histogram
+	serializationErrors metric.Int64Counter
+	versionMismatches   metric.Int64Counter
+	loads               metric.Int64Counter
+	localLookups        metric.Int64Counter
+}
+
+var metrics = newCacheMetrics()
+
+var tracer = otel.Tracer(instrumentationName)
+
+func newCacheMetrics() *cacheMetrics {
+	meter := otel.Meter(instrumentationName)
+	m := &cacheMetrics{}
+	m.lookups, _ = meter.Int64Counter("cupid.cache.lookups",
+		metric.WithDescription("Number of cache lookups by namespace and result: hit, stale, miss or error"))
+	m.duration, _ = meter.Float64Histogram("cupid.cache.duration",
+		metric.WithDescription("Duration of cache gets, sets and deletes"),
+		metric.WithUnit("s"))
+	m.payloadBytes, _ = meter.Int64Histogram("cupid.cache.payload",
+		metric.WithDescription("Size of cached values read and written"),
+		metric.WithUnit("By"))
+	m.serializationErrors, _ = meter.Int64Counter("cupid.cache.serialization_errors",
+		metric.WithDescription("Number of cached values that failed to encode or decode"))
+	m.versionMismatches, _ = meter.Int64Counter("cupid.cache.version_mismatches",
+		metric.WithDescription("Number of cached values ignored because another schema version wrote them"))
+	m.loads, _ = meter.Int64Counter("cupid.cache.loads",
+		metric.WithDescription("Number of loads run on cache misses and refreshes, after coalescing"))
+	m.localLookups, _ = meter.Int64Counter("cupid.cache.local.lookups",
+		metric.WithDescription("Number of lookups in the in-process tier by result: hit or miss"))
+	return m
+}
+
+func (m *cacheMetrics) recordLookup(ns, result string) {
+	if m.lookups == nil {
+		return
+	}
+	m.lookups.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("namespace", ns),
+		attribute.String("result", result),
+	))
+}
+
+func (m *cacheMetrics) recordDuration(ns, operation string, start time.Time) {
+	if m.duration == nil {
+		return
+	}
+	m.duration.Record(context.Background(), time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("namespace", ns),
+		attribute.String("operation", operation),
+	))
+}
+
+func (m *cacheMetrics) recordPayload(ns, operation string, size int) {
+	if m.payloadBytes == nil {
+		return
+	}
+	m.payloadBytes.Record(context.Background(), int64(size), metric.WithAttributes(
+		attribute.String("namespace", ns),
+		attribute.String("operation", operation),
+	))
+}
+
+func (m *cacheMetrics) recordSerializationError(ns, operation string) {
+	if m.serializationErrors == nil {
+		return
+	}
+	m.serializationErrors.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("namespace", ns),
+		attribute.String("operation", operation),
+	))
+}
+
+func (m *cacheMetrics) recordVersionMismatch(ns string) {
+	if m.versionMismatches == nil {
+		return
+	}
+	m.versionMismatches.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("namespace", ns),
+	))
+}
+
+func (m *cacheMetrics) recordLoad(ns string, err error) {
+	if m.loads == nil {
+		return
+	}
+	m.loads.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("namespace", ns),
+		attribute.Bool("error", err != nil),
+	))
+}
+
+func (m *cacheMetrics) recordLocalLookup(hit bool) {
+	if m.localLookups == nil {
+		return
+	}
+	result := resultMiss
+	if hit {
+		result = resultHit
+	}
+	m.localLookups.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("result", result),
+	))
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
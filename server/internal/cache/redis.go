@@ -2,9 +2,10 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -20,6 +21,8 @@ type ReviewCache interface {
 	Close() error
 }
 
+// RedisCache is a Store backed by Redis. It also implements ReviewCache on
+// top of ReviewsNamespace.
 type RedisCache struct {
 	client *redis.Client
 }
@@ -37,39 +40,87 @@ func NewRedisCache(addr string) *RedisCache {
 	return &RedisCache{client: rdb}
 }
 
-func (r *RedisCache) GetReviews(ctx context.Context, hotelID int) ([]client.Review, error) {
-	key := fmt.Sprintf("reviews:hotel:%d", hotelID)
-
-	val, err := r.client.Get(ctx, key).Result()
+func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	val, err := r.client.Get(ctx, key).Bytes()
 	if errors.Is(err, redis.Nil) {
-		return nil, nil // Cache miss
+		return nil, false, nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("redis get error: %w", err)
+		return nil, false, fmt.Errorf("redis get error: %w", err)
 	}
+	return val, true, nil
+}
+
+func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
 
-	var reviews []client.Review
-	if err := json.Unmarshal([]byte(val), &reviews); err != nil {
-		return nil, fmt.Errorf("json unmarshal error: %w", err)
+func (r *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
 	}
+	return r.client.Del(ctx, keys...).Err()
+}
 
-	return reviews, nil
+func (r *RedisCache) AddToIndex(ctx context.Context, index, key string, ttl time.Duration) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, index, key)
+		pipe.Expire(ctx, index, ttl)
+		return nil
+	})
+	return err
 }
 
-func (r *RedisCache) SetReviews(ctx context.Context, hotelID int, reviews []client.Review, ttl time.Duration) error {
-	key := fmt.Sprintf("reviews:hotel:%d", hotelID)
+// DeleteIndex implements Store. A key indexed while the index is deleted may
+// survive; it expires with its TTL.
+func (r *RedisCache) DeleteIndex(ctx context.Context, index string) error {
+	keys, err := r.client.SMembers(ctx, index).Result()
+	if err != nil {
+		return fmt.Errorf("redis smembers error: %w", err)
+	}
+	return r.client.Del(ctx, append(keys, index)...).Err()
+}
+
+// unlockScript deletes a lock only while it still holds the token of its
+// holder, so a lock that expired and was taken by another process survives.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
 
-	data, err := json.Marshal(reviews)
+// TryLock implements Locker with SET NX.
+func (r *RedisCache) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, false, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	ok, err := r.client.SetNX(ctx, key, token, ttl).Result()
 	if err != nil {
-		return fmt.Errorf("json marshal error: %w", err)
+		return nil, false, fmt.Errorf("redis setnx error: %w", err)
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	unlock := func() {
+		if err := unlockScript.Run(context.WithoutCancel(ctx), r.client, []string{key}, token).Err(); err != nil {
+			log.Printf("Warning: Failed to unlock %s: %v", key, err)
+		}
 	}
+	return unlock, true, nil
+}
 
-	return r.client.Set(ctx, key, data, ttl).Err()
+func (r *RedisCache) GetReviews(ctx context.Context, hotelID int) ([]client.Review, error) {
+	reviews, _, err := New[[]client.Review](r, ReviewsNamespace).Get(ctx, Key(hotelID))
+	return reviews, err
+}
+
+func (r *RedisCache) SetReviews(ctx context.Context, hotelID int, reviews []client.Review, ttl time.Duration) error {
+	return New(r, ReviewsNamespace, WithTTL[[]client.Review](ttl)).Set(ctx, Key(hotelID), reviews)
 }
 
 func (r *RedisCache) DeleteReviews(ctx context.Context, hotelID int) error {
-	key := fmt.Sprintf("reviews:hotel:%d", hotelID)
-	return r.client.Del(ctx, key).Err()
+	return r.Delete(ctx, ReviewsNamespace.Key(Key(hotelID)))
 }
 
 func (r *RedisCache) Ping(ctx context.Context) error {
@@ -142,3 +142,96 @@ func TestRedisCache_ConcurrentAccess(t *testing.T) {
 		<-done
 	}
 }
+
+func TestRedisCache_Invalidate(t *testing.T) {
+	t.Parallel()
+
+	redisCache := NewRedisCache("localhost:6379")
+	ctx := context.Background()
+
+	if err := redisCache.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+	defer redisCache.Close()
+
+	hotelID := rand.Intn(1000000) + 4000000 //nolint:gosec // Test data only
+	t.Cleanup(func() {
+		_ = redisCache.DeleteReviews(ctx, hotelID)
+	})
+
+	reviews := []client.Review{{HotelID: hotelID, ReviewerName: "Stale", Rating: 3}}
+	require.NoError(t, redisCache.SetReviews(ctx, hotelID, reviews, time.Minute))
+
+	require.NoError(t, redisCache.Invalidate(ctx, Invalidation{HotelID: hotelID, Entity: EntityHotel}))
+	cached, err := redisCache.GetReviews(ctx, hotelID)
+	require.NoError(t, err)
+	assert.Equal(t, reviews, cached)
+
+	require.NoError(t, redisCache.Invalidate(ctx, Invalidation{HotelID: hotelID, Entity: EntityReviews}))
+	cached, err = redisCache.GetReviews(ctx, hotelID)
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestRedisCache_TryLock(t *testing.T) {
+	t.Parallel()
+
+	redisCache := NewRedisCache("localhost:6379")
+	ctx := context.Background()
+
+	if err := redisCache.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+	defer redisCache.Close()
+
+	key := Key("test", "lock", rand.Intn(1000000)) //nolint:gosec // Test data only
+
+	unlock, ok, err := redisCache.TryLock(ctx, key, time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, ok, err = redisCache.TryLock(ctx, key, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "lock is held")
+
+	unlock()
+	unlock, ok, err = redisCache.TryLock(ctx, key, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "lock is released")
+	unlock()
+}
+
+func TestRedisCache_SubscribeInvalidations(t *testing.T) {
+	t.Parallel()
+
+	redisCache := NewRedisCache("localhost:6379")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := redisCache.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+	defer redisCache.Close()
+
+	hotelID := rand.Intn(1000000) + 5000000 //nolint:gosec // Test data only
+	received := make(chan Invalidation, 10)
+	go func() {
+		_ = redisCache.SubscribeInvalidations(ctx, func(inv Invalidation) {
+			if inv.HotelID == hotelID {
+				received <- inv
+			}
+		})
+	}()
+
+	inv := Invalidation{HotelID: hotelID, Entity: EntityReviews}
+	// Publish until the subscription is established.
+	require.Eventually(t, func() bool {
+		require.NoError(t, redisCache.Invalidate(ctx, inv))
+		select {
+		case got := <-received:
+			return assert.Equal(t, inv, got)
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
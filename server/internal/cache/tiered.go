@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// Tiered is a Store that reads through an in-process MemoryStore to a shared
+// store, such as Redis. Writes go to both tiers. Invalidations are made on the
+// shared store, which relays them to the local tiers of other processes when
+// it is a Broadcaster.
+type Tiered struct {
+	local  *MemoryStore
+	shared Store
+}
+
+// Broadcaster relays invalidations between processes.
+type Broadcaster interface {
+	// SubscribeInvalidations calls fn with the invalidations of every process
+	// until ctx is done.
+	SubscribeInvalidations(ctx context.Context, fn func(Invalidation)) error
+}
+
+// NewTiered returns a store caching the values of shared in local. local
+// needs a max TTL, which bounds how long it serves values that another process
+// changed while their invalidation was not received.
+func NewTiered(local *MemoryStore, shared Store) *Tiered {
+	return &Tiered{local: local, shared: shared}
+}
+
+func (t *Tiered) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, ok, _ := t.local.Get(ctx, key)
+	metrics.recordLocalLookup(ok)
+	if ok {
+		return value, true, nil
+	}
+	value, ok, err := t.shared.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	// The remaining TTL of the shared entry is unknown; the local tier caps it.
+	_ = t.local.Set(ctx, key, value, t.local.maxTTL)
+	return value, true, nil
+}
+
+func (t *Tiered) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_ = t.local.Set(ctx, key, value, ttl)
+	return t.shared.Set(ctx, key, value, ttl)
+}
+
+func (t *Tiered) Delete(ctx context.Context, keys ...string) error {
+	_ = t.local.Delete(ctx, keys...)
+	return t.shared.Delete(ctx, keys...)
+}
+
+func (t *Tiered) AddToIndex(ctx context.Context, index, key string, ttl time.Duration) error {
+	return t.shared.AddToIndex(ctx, index, key, ttl)
+}
+
+func (t *Tiered) DeleteIndex(ctx context.Context, index string) error {
+	_ = t.local.DeleteIndex(ctx, index)
+	return t.shared.DeleteIndex(ctx, index)
+}
+
+// LastInvalidation implements InvalidationTracker with the local tier, which
+// sees the invalidations of this process and those received from others.
+func (t *Tiered) LastInvalidation(key string) (uint64, time.Time) {
+	return t.local.LastInvalidation(key)
+}
+
+// Invalidate evicts the entity from both tiers, through the shared store's
+// Invalidator when it has one so that other processes are told.
+func (t *Tiered) Invalidate(ctx context.Context, inv Invalidation) error {
+	err := t.local.Invalidate(ctx, inv)
+	if invalidator, ok := t.shared.(Invalidator); ok {
+		return errors.Join(err, invalidator.Invalidate(ctx, inv))
+	}
+	return errors.Join(err, Invalidate(ctx, t.shared, inv))
+}
+
+// TryLock implements Locker with the lock of the shared store. Without one,
+// no other process shares the store, and the lock is always taken.
+func (t *Tiered) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	if locker, ok := t.shared.(Locker); ok {
+		return locker.TryLock(ctx, key, ttl)
+	}
+	return func() {}, true, nil
+}
+
+// Subscribe evicts the invalidations of other processes from the local tier
+// until ctx is done. It does nothing unless the shared store is a Broadcaster.
+func (t *Tiered) Subscribe(ctx context.Context) {
+	broadcaster, ok := t.shared.(Broadcaster)
+	if !ok {
+		return
+	}
+	err := broadcaster.SubscribeInvalidations(ctx, func(inv Invalidation) {
+		_ = t.local.Invalidate(ctx, inv)
+	})
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Warning: Cache invalidation subscription ended: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// broadcastStore is a shared memoryStore whose invalidations reach the
+// subscribers of every process using it.
+type broadcastStore struct {
+	*memoryStore
+
+	mu   sync.Mutex
+	subs []chan Invalidation
+}
+
+func (b *broadcastStore) Invalidate(ctx context.Context, inv Invalidation) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range b.subs {
+		sub <- inv
+	}
+	return Invalidate(ctx, b, inv)
+}
+
+func (b *broadcastStore) SubscribeInvalidations(ctx context.Context, fn func(Invalidation)) error {
+	sub := make(chan Invalidation, 10)
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case inv := <-sub:
+			fn(inv)
+		}
+	}
+}
+
+func (b *broadcastStore) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+func TestTiered_ReadThrough(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	shared := newMemoryStore()
+	local := NewMemoryStore(1<<10, time.Minute)
+	tiered := NewTiered(local, shared)
+
+	require.NoError(t, shared.Set(ctx, "k", []byte("v"), time.Hour))
+	value, ok, err := tiered.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v"), value)
+
+	// The value is now served locally.
+	require.NoError(t, shared.Delete(ctx, "k"))
+	_, ok, _ = tiered.Get(ctx, "k")
+	assert.True(t, ok)
+
+	require.NoError(t, tiered.Set(ctx, "w", []byte("x"), time.Hour))
+	_, ok, _ = local.Get(ctx, "w")
+	assert.True(t, ok)
+	_, ok, _ = shared.Get(ctx, "w")
+	assert.True(t, ok)
+
+	require.NoError(t, tiered.Delete(ctx, "w"))
+	_, ok, _ = tiered.Get(ctx, "w")
+	assert.False(t, ok)
+
+	unlock, ok, err := tiered.TryLock(ctx, "lock", time.Second)
+	require.NoError(t, err)
+	assert.True(t, ok, "without a shared lock the lock is always taken")
+	unlock()
+}
+
+func TestTiered_InvalidatesOtherProcesses(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shared := &broadcastStore{memoryStore: newMemoryStore()}
+	writer := NewTiered(NewMemoryStore(1<<10, time.Minute), shared)
+	reader := NewTiered(NewMemoryStore(1<<10, time.Minute), shared)
+	go reader.Subscribe(ctx)
+	require.Eventually(t, func() bool { return shared.subscribers() == 1 }, time.Second, time.Millisecond)
+
+	key := ReviewsNamespace.Key(Key(7))
+	require.NoError(t, writer.Set(ctx, key, []byte("old"), time.Hour))
+	_, ok, _ := reader.Get(ctx, key)
+	require.True(t, ok)
+
+	require.NoError(t, writer.Invalidate(ctx, Invalidation{HotelID: 7, Entity: EntityReviews}))
+	require.Eventually(t, func() bool {
+		_, ok, _ := reader.Get(ctx, key)
+		return !ok
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,233 @@
+// Package catalog resolves the set of hotels a batch command works on, from
+// the command line, a CSV or NDJSON file, or the hotel_catalog table.
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrConflictingSources is returned when more than one hotel ID source is given.
+var ErrConflictingSources = errors.New("only one of --ids, --ids-file and --from-db can be used")
+
+// HotelIDLister lists the hotels tracked in the database catalog.
+// It is implemented by database.HotelRepository.
+type HotelIDLister interface {
+	GetCatalogHotelIDs(ctx context.Context, activeOnly bool) ([]int, error)
+}
+
+// Source selects where hotel IDs come from. With no field set, the active
+// hotels of the database catalog are used.
+type Source struct {
+	// IDs is a comma separated list of hotel IDs.
+	IDs string
+	// File is a CSV or NDJSON file of hotel IDs.
+	File string
+	// FromDB reads the active hotels of the hotel_catalog table.
+	FromDB bool
+}
+
+// Load returns the hotel IDs of the source, deduplicated and in source order.
+func (s Source) Load(ctx context.Context, lister HotelIDLister) ([]int, error) {
+	set := 0
+	for _, given := range []bool{s.IDs != "", s.File != "", s.FromDB} {
+		if given {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, ErrConflictingSources
+	}
+
+	var hotelIDs []int
+	var err error
+	switch {
+	case s.IDs != "":
+		hotelIDs, err = ParseIDs(s.IDs)
+	case s.File != "":
+		hotelIDs, err = ReadFile(s.File)
+	default:
+		if lister == nil {
+			return nil, errors.New("no database configured to read the hotel catalog")
+		}
+		hotelIDs, err = lister.GetCatalogHotelIDs(ctx, true)
+		if err != nil {
+			err = fmt.Errorf("failed to read hotel catalog: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dedupe(hotelIDs), nil
+}
+
+// Selected reports whether any source was given explicitly.
+func (s Source) Selected() bool {
+	return s.IDs != "" || s.File != "" || s.FromDB
+}
+
+// Description names the source for log lines.
+func (s Source) Description() string {
+	switch {
+	case s.IDs != "":
+		return "--ids"
+	case s.File != "":
+		return s.File
+	default:
+		return "hotel_catalog"
+	}
+}
+
+// ParseIDs parses a comma separated list of hotel IDs.
+func ParseIDs(list string) ([]int, error) {
+	var hotelIDs []int
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		hotelID, err := parseID(field)
+		if err != nil {
+			return nil, err
+		}
+		hotelIDs = append(hotelIDs, hotelID)
+	}
+	return hotelIDs, nil
+}
+
+// ReadFile reads hotel IDs from a file. Files ending in .ndjson or .jsonl, or
+// starting with a JSON object, are read as NDJSON; anything else as CSV.
+func ReadFile(path string) ([]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ids file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return ParseNDJSON(bytes.NewReader(data))
+	case ".csv":
+		return ParseCSV(bytes.NewReader(data))
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return ParseNDJSON(bytes.NewReader(data))
+	}
+	return ParseCSV(bytes.NewReader(data))
+}
+
+// ParseCSV reads hotel IDs from CSV. The IDs are taken from the hotel_id
+// column when the first row is a header, otherwise from the first column.
+// Lines starting with # are ignored.
+func ParseCSV(r io.Reader) ([]int, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	column := 0
+	if _, err := strconv.Atoi(strings.TrimSpace(records[0][0])); err != nil {
+		column = -1
+		for i, name := range records[0] {
+			if strings.EqualFold(strings.TrimSpace(name), "hotel_id") {
+				column = i
+				break
+			}
+		}
+		if column < 0 {
+			return nil, errors.New("csv header has no hotel_id column")
+		}
+		records = records[1:]
+	}
+
+	var hotelIDs []int
+	for i, record := range records {
+		if column >= len(record) || strings.TrimSpace(record[column]) == "" {
+			continue
+		}
+		hotelID, err := parseID(strings.TrimSpace(record[column]))
+		if err != nil {
+			return nil, fmt.Errorf("csv record %d: %w", i+1, err)
+		}
+		hotelIDs = append(hotelIDs, hotelID)
+	}
+	return hotelIDs, nil
+}
+
+// ParseNDJSON reads hotel IDs from newline delimited JSON. Each line is
+// either an object with a hotel_id field or a bare number.
+func ParseNDJSON(r io.Reader) ([]int, error) {
+	var hotelIDs []int
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var hotelID int
+		if text[0] == '{' {
+			var record struct {
+				HotelID *int `json:"hotel_id"`
+			}
+			if err := json.Unmarshal(text, &record); err != nil {
+				return nil, fmt.Errorf("ndjson line %d: %w", line, err)
+			}
+			if record.HotelID == nil {
+				return nil, fmt.Errorf("ndjson line %d: missing hotel_id", line)
+			}
+			hotelID = *record.HotelID
+		} else if err := json.Unmarshal(text, &hotelID); err != nil {
+			return nil, fmt.Errorf("ndjson line %d: %w", line, err)
+		}
+
+		if hotelID <= 0 {
+			return nil, fmt.Errorf("ndjson line %d: invalid hotel ID %d", line, hotelID)
+		}
+		hotelIDs = append(hotelIDs, hotelID)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ndjson: %w", err)
+	}
+	return hotelIDs, nil
+}
+
+func parseID(field string) (int, error) {
+	hotelID, err := strconv.Atoi(field)
+	if err != nil || hotelID <= 0 {
+		return 0, fmt.Errorf("invalid hotel ID %q", field)
+	}
+	return hotelID, nil
+}
+
+func dedupe(hotelIDs []int) []int {
+	seen := make(map[int]bool, len(hotelIDs))
+	unique := hotelIDs[:0]
+	for _, hotelID := range hotelIDs {
+		if seen[hotelID] {
+			continue
+		}
+		seen[hotelID] = true
+		unique = append(unique, hotelID)
+	}
+	return unique
+}
@@ -0,0 +1,140 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubLister struct {
+	ids []int
+	err error
+}
+
+func (s stubLister) GetCatalogHotelIDs(_ context.Context, activeOnly bool) ([]int, error) {
+	if !activeOnly {
+		return nil, errors.New("expected active hotels only")
+	}
+	return s.ids, s.err
+}
+
+func TestParseIDs(t *testing.T) {
+	t.Parallel()
+
+	ids, err := ParseIDs(" 1641879, 317597,,1202743 ")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1641879, 317597, 1202743}, ids)
+
+	_, err = ParseIDs("1641879,abc")
+	assert.ErrorContains(t, err, `invalid hotel ID "abc"`)
+
+	_, err = ParseIDs("-1")
+	assert.Error(t, err)
+}
+
+func TestParseCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr string
+	}{
+		{name: "bare ids", input: "1641879\n317597\n", want: []int{1641879, 317597}},
+		{name: "header", input: "hotel_id\n1641879\n317597\n", want: []int{1641879, 317597}},
+		{name: "header column", input: "name,hotel_id,priority\nA,1641879,1\nB,317597,0\n", want: []int{1641879, 317597}},
+		{name: "comments and blanks", input: "# tracked hotels\n1641879\n\n317597\n", want: []int{1641879, 317597}},
+		{name: "missing column", input: "name,priority\nA,1\n", wantErr: "no hotel_id column"},
+		{name: "bad id", input: "hotel_id\nabc\n", wantErr: "csv record 1"},
+		{name: "empty", input: "", want: nil},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseCSV(strings.NewReader(tc.input))
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseNDJSON(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseNDJSON(strings.NewReader("{\"hotel_id\": 1641879, \"name\": \"A\"}\n\n317597\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1641879, 317597}, got)
+
+	_, err = ParseNDJSON(strings.NewReader("{\"name\": \"A\"}\n"))
+	assert.ErrorContains(t, err, "ndjson line 1: missing hotel_id")
+
+	_, err = ParseNDJSON(strings.NewReader("1641879\n{broken\n"))
+	assert.ErrorContains(t, err, "ndjson line 2")
+}
+
+func TestReadFile_DetectsFormat(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"ids.csv":    "hotel_id\n1641879\n",
+		"ids.ndjson": "{\"hotel_id\": 1641879}\n",
+		"ids.txt":    "{\"hotel_id\": 1641879}\n",
+		"ids":        "1641879\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		got, err := ReadFile(path)
+		require.NoError(t, err, name)
+		assert.Equal(t, []int{1641879}, got, name)
+	}
+}
+
+func TestSource_Load(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	lister := stubLister{ids: []int{1641879, 317597}}
+
+	got, err := Source{}.Load(ctx, lister)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1641879, 317597}, got)
+
+	got, err = Source{IDs: "317597,317597,1202743"}.Load(ctx, lister)
+	require.NoError(t, err)
+	assert.Equal(t, []int{317597, 1202743}, got)
+
+	_, err = Source{IDs: "1", FromDB: true}.Load(ctx, lister)
+	assert.ErrorIs(t, err, ErrConflictingSources)
+
+	_, err = Source{FromDB: true}.Load(ctx, stubLister{err: errors.New("boom")})
+	assert.ErrorContains(t, err, "failed to read hotel catalog")
+
+	_, err = Source{FromDB: true}.Load(ctx, nil)
+	assert.Error(t, err)
+}
+
+func TestSource_Selected(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, Source{}.Selected())
+	assert.True(t, Source{IDs: "1"}.Selected())
+	assert.True(t, Source{File: "ids.csv"}.Selected())
+	assert.True(t, Source{FromDB: true}.Selected())
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Languages are the translation languages synced from Cupid and served by the API.
+var Languages = []string{"fr", "es", "en"}
+
+// apiHeaders returns the headers required by the Cupid content API.
+func (c *Client) apiHeaders() http.Header {
+	headers := http.Header{}
+	headers.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		headers.Set("x-api-key", c.apiKey)
+	}
+	return headers
+}
+
+// PropertyPath returns the API path of a hotel property.
+func PropertyPath(hotelID int) string {
+	return fmt.Sprintf("/v3.0/property/%d", hotelID)
+}
+
+// ReviewsPath returns the API path of the reviews of a hotel.
+func ReviewsPath(hotelID, count int) string {
+	return fmt.Sprintf("/v3.0/property/reviews/%d/%d", hotelID, count)
+}
+
+// TranslationsPath returns the API path of the translations of a hotel.
+func TranslationsPath(hotelID int, lang string) string {
+	return fmt.Sprintf("/v3.0/property/%d/lang/%s", hotelID, lang)
+}
+
+// GetProperty fetches the full content of a hotel from /v3.0/property/{hotelID}.
+// With a ValidatorStore configured it returns ErrNotModified when the property
+// is unchanged since the last successful fetch.
+func (c *Client) GetProperty(ctx context.Context, hotelID int) (*Property, error) {
+	body, _, err := c.Do(ctx, http.MethodGet, PropertyPath(hotelID), nil, c.apiHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	property, err := ParseProperty(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse property: %w", err)
+	}
+	return property, nil
+}
+
+// GetReviews fetches up to count reviews of a hotel from /v3.0/property/reviews/{hotelID}/{count}.
+// An empty response body yields no reviews and no error.
+func (c *Client) GetReviews(ctx context.Context, hotelID, count int) ([]Review, error) {
+	body, _, err := c.Do(ctx, http.MethodGet, ReviewsPath(hotelID, count), nil, c.apiHeaders())
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	reviews, err := ParseReviews(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// GetTranslations fetches the hotel translations for lang from /v3.0/property/{hotelID}/lang/{lang}.
+// Every returned translation is tagged with the requested language code.
+func (c *Client) GetTranslations(ctx context.Context, hotelID int, lang string) ([]Translation, error) {
+	body, _, err := c.Do(ctx, http.MethodGet, TranslationsPath(hotelID, lang), nil, c.apiHeaders())
+	if err != nil {
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	translations, err := ParseTranslations(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse translations: %w", err)
+	}
+	for i := range translations {
+		translations[i].LanguageCode = lang
+	}
+	return translations, nil
+}
@@ -0,0 +1,277 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/compress"
+)
+
+// RawPayload is an upstream response body as it was received.
+type RawPayload struct {
+	URL        string
+	Method     string
+	StatusCode int
+	RequestID  string
+	FetchedAt  time.Time
+	Body       []byte
+}
+
+// PayloadFilter selects archived payloads.
+type PayloadFilter struct {
+	// Since and Until bound FetchedAt; zero values leave that side open.
+	Since time.Time
+	Until time.Time
+	// SuccessfulOnly skips non-2xx responses.
+	SuccessfulOnly bool
+	// LatestOnly keeps only the most recent matching payload of every URL.
+	LatestOnly bool
+}
+
+// Matches reports whether a payload fetched at fetchedAt with statusCode passes the filter.
+func (f PayloadFilter) Matches(fetchedAt time.Time, statusCode int) bool {
+	if f.SuccessfulOnly && (statusCode < 200 || statusCode > 299) {
+		return false
+	}
+	return (f.Since.IsZero() || !fetchedAt.Before(f.Since)) && (f.Until.IsZero() || fetchedAt.Before(f.Until))
+}
+
+// PayloadArchive keeps raw response bodies for auditing and replay.
+type PayloadArchive interface {
+	ArchivePayload(ctx context.Context, payload RawPayload) error
+	// WalkPayloads calls fn with the payloads matching filter, oldest first.
+	WalkPayloads(ctx context.Context, filter PayloadFilter, fn func(RawPayload) error) error
+}
+
+// WithPayloadArchive archives the body of every response except 304s.
+// Archive failures are logged and do not fail the request.
+func WithPayloadArchive(archive PayloadArchive) Option {
+	return func(c *Client) { c.archive = archive }
+}
+
+// archivePayload records a response of fullURL in the archive, if one is configured.
+func (c *Client) archivePayload(ctx context.Context, method, fullURL string, resp *http.Response, body []byte) error {
+	if c.archive == nil || resp.StatusCode == http.StatusNotModified {
+		return nil
+	}
+	return c.archive.ArchivePayload(ctx, RawPayload{
+		URL:        fullURL,
+		Method:     method,
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		FetchedAt:  time.Now().UTC(),
+		Body:       body,
+	})
+}
+
+// APIPath is a parsed Cupid content API path.
+type APIPath struct {
+	// Endpoint is "property", "reviews" or "translations".
+	Endpoint string
+	HotelID  int
+	Lang     string
+}
+
+var (
+	reviewsPathRe      = regexp.MustCompile(`^/v3\.0/property/reviews/(\d+)/\d+$`)
+	translationsPathRe = regexp.MustCompile(`^/v3\.0/property/(\d+)/lang/([A-Za-z-]+)$`)
+	propertyPathRe     = regexp.MustCompile(`^/v3\.0/property/(\d+)$`)
+)
+
+// ParseAPIPath recognises the paths built by PropertyPath, ReviewsPath and
+// TranslationsPath. rawURL may be a full URL or a path.
+func ParseAPIPath(rawURL string) (APIPath, bool) {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+
+	if m := reviewsPathRe.FindStringSubmatch(path); m != nil {
+		hotelID, _ := strconv.Atoi(m[1])
+		return APIPath{Endpoint: "reviews", HotelID: hotelID}, true
+	}
+	if m := translationsPathRe.FindStringSubmatch(path); m != nil {
+		hotelID, _ := strconv.Atoi(m[1])
+		return APIPath{Endpoint: "translations", HotelID: hotelID, Lang: m[2]}, true
+	}
+	if m := propertyPathRe.FindStringSubmatch(path); m != nil {
+		hotelID, _ := strconv.Atoi(m[1])
+		return APIPath{Endpoint: "property", HotelID: hotelID}, true
+	}
+	return APIPath{}, false
+}
+
+// FileArchive keeps payloads on disk as gzipped JSON documents, one per
+// response, under a directory per day.
+type FileArchive struct {
+	dir string
+}
+
+// NewFileArchive creates an archive rooted at dir, creating it if needed.
+func NewFileArchive(dir string) (*FileArchive, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create archive dir: %w", err)
+	}
+	return &FileArchive{dir: dir}, nil
+}
+
+// archivedPayload is the on-disk form of a RawPayload.
+type archivedPayload struct {
+	URL        string    `json:"url"`
+	Method     string    `json:"method"`
+	StatusCode int       `json:"status_code"`
+	RequestID  string    `json:"request_id,omitempty"`
+	FetchedAt  time.Time `json:"fetched_at"`
+	Body       string    `json:"body"`
+}
+
+// fileName is <fetched at, unix nanoseconds>-<status>-<URL hash>.json.gz, so
+// files can be filtered and grouped by URL without being read.
+func fileName(p RawPayload) string {
+	sum := sha256.Sum256([]byte(p.URL))
+	return fmt.Sprintf("%d-%d-%s.json.gz", p.FetchedAt.UnixNano(), p.StatusCode, hex.EncodeToString(sum[:8]))
+}
+
+// ArchivePayload implements PayloadArchive.
+func (a *FileArchive) ArchivePayload(_ context.Context, p RawPayload) error {
+	data, err := json.Marshal(archivedPayload{
+		URL:        p.URL,
+		Method:     p.Method,
+		StatusCode: p.StatusCode,
+		RequestID:  p.RequestID,
+		FetchedAt:  p.FetchedAt,
+		Body:       string(p.Body),
+	})
+	if err != nil {
+		return err
+	}
+	compressed, err := compress.Gzip(data)
+	if err != nil {
+		return fmt.Errorf("failed to compress payload: %w", err)
+	}
+
+	dir := filepath.Join(a.dir, p.FetchedAt.UTC().Format("2006/01/02"))
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create archive dir: %w", err)
+	}
+	// Write to a temp file and rename so a concurrent replay never reads partial data.
+	tmp, err := os.CreateTemp(dir, "payload-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(compressed); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, fileName(p)))
+}
+
+type archiveFile struct {
+	path      string
+	fetchedAt time.Time
+	urlHash   string
+}
+
+// WalkPayloads implements PayloadArchive.
+func (a *FileArchive) WalkPayloads(ctx context.Context, filter PayloadFilter, fn func(RawPayload) error) error {
+	var files []archiveFile
+	err := filepath.WalkDir(a.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name, ok := strings.CutSuffix(d.Name(), ".json.gz")
+		if !ok {
+			return nil
+		}
+		parts := strings.Split(name, "-")
+		if len(parts) != 3 {
+			return nil
+		}
+		nanos, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return nil
+		}
+		status, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil
+		}
+		fetchedAt := time.Unix(0, nanos).UTC()
+		if filter.Matches(fetchedAt, status) {
+			files = append(files, archiveFile{path: path, fetchedAt: fetchedAt, urlHash: parts[2]})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk archive: %w", err)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].fetchedAt.Before(files[j].fetchedAt) })
+	if filter.LatestOnly {
+		latest := make(map[string]int, len(files))
+		for i, f := range files {
+			latest[f.urlHash] = i
+		}
+		kept := files[:0]
+		for i, f := range files {
+			if latest[f.urlHash] == i {
+				kept = append(kept, f)
+			}
+		}
+		files = kept
+	}
+
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p, err := readArchiveFile(f.path)
+		if err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readArchiveFile(path string) (RawPayload, error) {
+	compressed, err := os.ReadFile(path)
+	if err != nil {
+		return RawPayload{}, err
+	}
+	data, err := compress.Gunzip(compressed)
+	if err != nil {
+		return RawPayload{}, fmt.Errorf("failed to decompress %s: %w", path, err)
+	}
+	var p archivedPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return RawPayload{}, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return RawPayload{
+		URL:        p.URL,
+		Method:     p.Method,
+		StatusCode: p.StatusCode,
+		RequestID:  p.RequestID,
+		FetchedAt:  p.FetchedAt,
+		Body:       []byte(p.Body),
+	}, nil
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Do_ArchivesPayloads(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-"+r.URL.Path[len(r.URL.Path)-1:])
+		if r.URL.Path == PropertyPath(2) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"hotel_id":1,"hotel_name":"Archived"}`))
+	}))
+	defer ts.Close()
+
+	archive, err := NewFileArchive(t.TempDir())
+	require.NoError(t, err)
+	c, err := New(ts.URL, WithPayloadArchive(archive))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = c.GetProperty(ctx, 1)
+	require.NoError(t, err)
+	_, err = c.GetProperty(ctx, 2)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	var payloads []RawPayload
+	require.NoError(t, archive.WalkPayloads(ctx, PayloadFilter{}, func(p RawPayload) error {
+		payloads = append(payloads, p)
+		return nil
+	}))
+	require.Len(t, payloads, 2)
+
+	assert.Equal(t, ts.URL+PropertyPath(1), payloads[0].URL)
+	assert.Equal(t, http.MethodGet, payloads[0].Method)
+	assert.Equal(t, http.StatusOK, payloads[0].StatusCode)
+	assert.Equal(t, "req-1", payloads[0].RequestID)
+	assert.JSONEq(t, `{"hotel_id":1,"hotel_name":"Archived"}`, string(payloads[0].Body))
+	assert.False(t, payloads[0].FetchedAt.IsZero())
+
+	assert.Equal(t, http.StatusNotFound, payloads[1].StatusCode)
+	assert.Equal(t, "req-2", payloads[1].RequestID)
+}
+
+func TestFileArchive_WalkPayloadsFilters(t *testing.T) {
+	t.Parallel()
+
+	archive, err := NewFileArchive(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, p := range []RawPayload{
+		{URL: "https://api/v3.0/property/1", StatusCode: 200, Body: []byte(`"v1"`)},
+		{URL: "https://api/v3.0/property/2", StatusCode: 200, Body: []byte(`"other"`)},
+		{URL: "https://api/v3.0/property/1", StatusCode: 200, Body: []byte(`"v2"`)},
+		{URL: "https://api/v3.0/property/1", StatusCode: 503, Body: []byte(`"down"`)},
+	} {
+		p.FetchedAt = base.Add(time.Duration(i) * time.Hour)
+		require.NoError(t, archive.ArchivePayload(ctx, p))
+	}
+
+	bodies := func(filter PayloadFilter) []string {
+		var out []string
+		require.NoError(t, archive.WalkPayloads(ctx, filter, func(p RawPayload) error {
+			out = append(out, string(p.Body))
+			return nil
+		}))
+		return out
+	}
+
+	assert.Equal(t, []string{`"v1"`, `"other"`, `"v2"`, `"down"`}, bodies(PayloadFilter{}))
+	assert.Equal(t, []string{`"other"`, `"v2"`}, bodies(PayloadFilter{SuccessfulOnly: true, LatestOnly: true}))
+	assert.Equal(t, []string{`"v1"`, `"other"`}, bodies(PayloadFilter{Until: base.Add(2 * time.Hour)}))
+	assert.Equal(t, []string{`"v2"`, `"down"`}, bodies(PayloadFilter{Since: base.Add(2 * time.Hour)}))
+}
+
+func TestParseAPIPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url      string
+		expected APIPath
+		ok       bool
+	}{
+		{"https://content-api.cupid.travel" + PropertyPath(1641879), APIPath{Endpoint: "property", HotelID: 1641879}, true},
+		{ReviewsPath(12, 100), APIPath{Endpoint: "reviews", HotelID: 12}, true},
+		{TranslationsPath(12, "fr"), APIPath{Endpoint: "translations", HotelID: 12, Lang: "fr"}, true},
+		{"/v3.0/property/abc", APIPath{}, false},
+		{"/health", APIPath{}, false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.url, func(t *testing.T) {
+			t.Parallel()
+			path, ok := ParseAPIPath(tc.url)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
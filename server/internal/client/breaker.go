@@ -0,0 +1,208 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Do when the circuit breaker for the target host is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// StateClosed lets every request through.
+	StateClosed BreakerState = iota
+	// StateOpen rejects every request until the open timeout elapses.
+	StateOpen
+	// StateHalfOpen lets a limited number of probe requests through.
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerSettings configures the per-host circuit breaker.
+type BreakerSettings struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before probing again.
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is the number of concurrent probes allowed while half-open.
+	HalfOpenMaxRequests int
+}
+
+// DefaultBreakerSettings opens after 5 consecutive failures and probes again after 30s.
+func DefaultBreakerSettings() BreakerSettings {
+	return BreakerSettings{
+		FailureThreshold:    5,
+		OpenTimeout:         30 * time.Second,
+		HalfOpenMaxRequests: 1,
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker per target host.
+func WithCircuitBreaker(settings BreakerSettings) Option {
+	return func(c *Client) {
+		if settings.FailureThreshold <= 0 {
+			settings.FailureThreshold = 1
+		}
+		if settings.HalfOpenMaxRequests <= 0 {
+			settings.HalfOpenMaxRequests = 1
+		}
+		c.breakerSettings = &settings
+		c.breakers = make(map[string]*circuitBreaker)
+	}
+}
+
+type circuitBreaker struct {
+	host     string
+	settings BreakerSettings
+	now      func() time.Time
+
+	mu               sync.Mutex
+	state            BreakerState
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+}
+
+func newCircuitBreaker(host string, settings BreakerSettings) *circuitBreaker {
+	return &circuitBreaker{host: host, settings: settings, now: time.Now}
+}
+
+// breakerFor returns the circuit breaker for host, or nil when breakers are disabled.
+func (c *Client) breakerFor(host string) *circuitBreaker {
+	if c.breakerSettings == nil {
+		return nil
+	}
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+	cb, ok := c.breakers[host]
+	if !ok {
+		cb = newCircuitBreaker(host, *c.breakerSettings)
+		c.breakers[host] = cb
+	}
+	return cb
+}
+
+// State returns the current state of the breaker.
+func (cb *circuitBreaker) State() BreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state
+}
+
+// allow reports whether a request may proceed. Every allowed request must be
+// followed by exactly one call to record or release.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateOpen {
+		if cb.now().Sub(cb.openedAt) < cb.settings.OpenTimeout {
+			metrics.recordRejection(cb.host)
+			return ErrCircuitOpen
+		}
+		cb.transition(StateHalfOpen)
+	}
+
+	if cb.state == StateHalfOpen {
+		if cb.halfOpenInFlight >= cb.settings.HalfOpenMaxRequests {
+			metrics.recordRejection(cb.host)
+			return ErrCircuitOpen
+		}
+		cb.halfOpenInFlight++
+	}
+	return nil
+}
+
+// record reports the outcome of an allowed request.
+func (cb *circuitBreaker) record(failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateHalfOpen && cb.halfOpenInFlight > 0 {
+		cb.halfOpenInFlight--
+	}
+
+	if !failed {
+		cb.failures = 0
+		if cb.state != StateClosed {
+			cb.transition(StateClosed)
+		}
+		return
+	}
+
+	cb.failures++
+	switch cb.state {
+	case StateHalfOpen:
+		cb.transition(StateOpen)
+	case StateClosed:
+		if cb.failures >= cb.settings.FailureThreshold {
+			cb.transition(StateOpen)
+		}
+	case StateOpen:
+	}
+}
+
+// recordOutcome reports the outcome of a request sent through the breaker.
+// Transport errors, such as refused connections, DNS failures and timeouts,
+// and 5xx responses are failures. 429 responses and requests cancelled by the
+// caller say nothing about the host's health and leave the failure count
+// alone: throttling is handled by the rate limiter and Retry-After backoff.
+func (cb *circuitBreaker) recordOutcome(ctx context.Context, resp *http.Response, err error) {
+	switch {
+	case ctx.Err() != nil, resp != nil && resp.StatusCode == http.StatusTooManyRequests:
+		cb.release()
+	case resp == nil:
+		cb.record(err != nil)
+	default:
+		// The host answered; a 2xx fails only when its body could not be read.
+		success := resp.StatusCode >= 200 && resp.StatusCode < 300
+		cb.record(resp.StatusCode >= 500 || (success && err != nil))
+	}
+}
+
+// release gives back an allowed request that was never sent.
+func (cb *circuitBreaker) release() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == StateHalfOpen && cb.halfOpenInFlight > 0 {
+		cb.halfOpenInFlight--
+	}
+}
+
+// transition must be called with cb.mu held.
+func (cb *circuitBreaker) transition(to BreakerState) {
+	from := cb.state
+	if from == to {
+		return
+	}
+	cb.state = to
+	switch to {
+	case StateOpen:
+		cb.openedAt = cb.now()
+	case StateClosed:
+		cb.failures = 0
+	case StateHalfOpen:
+	}
+	cb.halfOpenInFlight = 0
+	log.Printf("circuit breaker for %s: %s -> %s", cb.host, from, to)
+	metrics.recordTransition(cb.host, from, to)
+}
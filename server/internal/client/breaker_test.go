@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_StateTransitions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	cb := newCircuitBreaker("example.com", BreakerSettings{
+		FailureThreshold:    2,
+		OpenTimeout:         time.Minute,
+		HalfOpenMaxRequests: 1,
+	})
+	cb.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, cb.allow())
+		cb.record(true)
+	}
+	assert.Equal(t, StateOpen, cb.State())
+	assert.ErrorIs(t, cb.allow(), ErrCircuitOpen)
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, cb.allow())
+	assert.Equal(t, StateHalfOpen, cb.State())
+	assert.ErrorIs(t, cb.allow(), ErrCircuitOpen, "only one probe allowed while half-open")
+
+	cb.record(true)
+	assert.Equal(t, StateOpen, cb.State())
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, cb.allow())
+	cb.record(false)
+	assert.Equal(t, StateClosed, cb.State())
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	t.Parallel()
+
+	cb := newCircuitBreaker("example.com", BreakerSettings{FailureThreshold: 2, OpenTimeout: time.Minute})
+	assert.NoError(t, cb.allow())
+	cb.record(true)
+	assert.NoError(t, cb.allow())
+	cb.record(false)
+	assert.NoError(t, cb.allow())
+	cb.record(true)
+	assert.Equal(t, StateClosed, cb.State())
+}
+
+func TestClient_Do_CircuitBreakerRejects(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithCircuitBreaker(BreakerSettings{FailureThreshold: 2, OpenTimeout: time.Hour}))
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, _, err = c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+		var ce *Error
+		assert.True(t, errors.As(err, &ce))
+	}
+
+	_, _, err = c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_Do_CircuitBreakerOpensOnTransportErrors(t *testing.T) {
+	t.Parallel()
+
+	// A server that is closed leaves its port refusing connections.
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close()
+
+	c, err := New(ts.URL, WithCircuitBreaker(BreakerSettings{FailureThreshold: 2, OpenTimeout: time.Hour}))
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, _, err = c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+
+	_, _, err = c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestClient_Do_CircuitBreakerIgnoresThrottlingAndCancellation(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithCircuitBreaker(BreakerSettings{FailureThreshold: 1, OpenTimeout: time.Hour}))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, _, err = c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+		var ce *Error
+		assert.True(t, errors.As(err, &ce), "429 responses do not open the breaker")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 2; i++ {
+		_, _, err = c.Do(ctx, http.MethodGet, "/path", nil, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, StateClosed, c.breakerFor(strings.TrimPrefix(ts.URL, "http://")).State())
+}
@@ -0,0 +1,277 @@
+package client
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Change operations reported by DiffProperties.
+const (
+	ChangeModified = "changed"
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+)
+
+// PropertyChange is a single field-level difference between two versions of a property.
+// Field is a JSON path such as "rating", "address.city" or "photos[https://...]".
+type PropertyChange struct {
+	Field string      `json:"field"`
+	Op    string      `json:"op"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// listKeys identifies the elements of list fields, so that reordering is not
+// reported and edits are reported per element rather than for the whole list.
+var listKeys = map[string]string{
+	"photos":         "url",
+	"facilities":     "facility_id",
+	"policies":       "id",
+	"rooms":          "id",
+	"bed_types":      "id",
+	"room_amenities": "amenities_id",
+}
+
+// Canonical returns the property as JSON with every unordered list sorted, so
+// two properties with the same content always encode to the same bytes.
+func (p *Property) Canonical() ([]byte, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	// Work on a copy so the caller's slices keep their order.
+	var c Property
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	sortPhotos(c.Photos)
+	slices.SortFunc(c.Facilities, func(a, b Facility) int {
+		return cmp.Or(cmp.Compare(a.FacilityID, b.FacilityID), strings.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(c.Policies, func(a, b Policy) int {
+		return cmp.Or(cmp.Compare(a.ID, b.ID), strings.Compare(a.PolicyType, b.PolicyType), strings.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(c.Rooms, func(a, b Room) int {
+		return cmp.Or(cmp.Compare(a.ID, b.ID), strings.Compare(a.RoomName, b.RoomName))
+	})
+	for i := range c.Rooms {
+		sortPhotos(c.Rooms[i].Photos)
+		slices.SortFunc(c.Rooms[i].BedTypes, func(a, b BedType) int {
+			return cmp.Or(cmp.Compare(a.ID, b.ID), strings.Compare(a.BedType, b.BedType))
+		})
+		slices.SortFunc(c.Rooms[i].RoomAmenities, func(a, b RoomAmenity) int {
+			return cmp.Or(cmp.Compare(a.AmenitiesID, b.AmenitiesID), strings.Compare(a.Name, b.Name))
+		})
+	}
+
+	return json.Marshal(&c)
+}
+
+// ContentHash returns the hex SHA-256 of the canonical encoding of the property.
+func (p *Property) ContentHash() (string, error) {
+	data, err := p.Canonical()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func sortPhotos(photos []Photo) {
+	slices.SortFunc(photos, func(a, b Photo) int {
+		return cmp.Or(strings.Compare(a.URL, b.URL), strings.Compare(a.HDURL, b.HDURL))
+	})
+}
+
+// DiffProperties returns the field-level changes from before to after, ordered by field.
+// Elements of keyed lists (photos, facilities, policies, rooms) are matched by key.
+func DiffProperties(before, after *Property) ([]PropertyChange, error) {
+	oldTree, err := toTree(before)
+	if err != nil {
+		return nil, err
+	}
+	newTree, err := toTree(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []PropertyChange
+	diffValues("", "", oldTree, newTree, &changes)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// SummarizeChanges renders changes as a short human readable line,
+// e.g. "rating 8.3→8.4; 2 photos added".
+func SummarizeChanges(changes []PropertyChange) string {
+	var parts []string
+	var groups []string
+	counted := map[string]map[string]bool{}
+	for _, c := range changes {
+		list, rest, keyed := strings.Cut(c.Field, "[")
+		if !keyed {
+			parts = append(parts, summarizeField(c))
+			continue
+		}
+		// Count list elements once, however many of their fields changed.
+		element, nested, _ := strings.Cut(rest, "]")
+		op := c.Op
+		if nested != "" {
+			op = ChangeModified
+		}
+		group := list + " " + op
+		if counted[group] == nil {
+			counted[group] = map[string]bool{}
+			groups = append(groups, group)
+		}
+		counted[group][element] = true
+	}
+	for _, group := range groups {
+		list, op, _ := strings.Cut(group, " ")
+		parts = append(parts, fmt.Sprintf("%d %s %s", len(counted[group]), list, op))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func summarizeField(c PropertyChange) string {
+	oldText, oldOK := scalarText(c.Old)
+	newText, newOK := scalarText(c.New)
+	if oldOK && newOK {
+		return fmt.Sprintf("%s %s→%s", c.Field, oldText, newText)
+	}
+	return c.Field + " " + c.Op
+}
+
+// scalarText formats short scalar values for summaries.
+func scalarText(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "∅", true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return fmt.Sprint(v), true
+	case string:
+		if len(v) > 40 {
+			return "", false
+		}
+		return fmt.Sprintf("%q", v), true
+	default:
+		return "", false
+	}
+}
+
+func toTree(p *Property) (interface{}, error) {
+	if p == nil {
+		return map[string]interface{}{}, nil
+	}
+	data, err := p.Canonical()
+	if err != nil {
+		return nil, err
+	}
+	// Keep numbers as json.Number so large IDs render as written.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// diffValues compares two decoded JSON values at path. name is the last
+// object key on the path and selects the list key for arrays.
+func diffValues(path, name string, before, after interface{}, changes *[]PropertyChange) {
+	oldMap, oldIsMap := before.(map[string]interface{})
+	newMap, newIsMap := after.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := make([]string, 0, len(oldMap)+len(newMap))
+		for k := range oldMap {
+			keys = append(keys, k)
+		}
+		for k := range newMap {
+			if _, ok := oldMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diffValues(joinPath(path, k), k, oldMap[k], newMap[k], changes)
+		}
+		return
+	}
+
+	oldList, oldIsList := before.([]interface{})
+	newList, newIsList := after.([]interface{})
+	if key, ok := listKeys[name]; ok && (oldIsList || before == nil) && (newIsList || after == nil) {
+		diffKeyedLists(path, key, oldList, newList, changes)
+		return
+	}
+
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+	*changes = append(*changes, PropertyChange{Field: path, Op: ChangeModified, Old: before, New: after})
+}
+
+func diffKeyedLists(path, key string, before, after []interface{}, changes *[]PropertyChange) {
+	oldByKey := indexByKey(before, key)
+	newByKey := indexByKey(after, key)
+
+	for _, k := range sortedKeys(oldByKey) {
+		elemPath := fmt.Sprintf("%s[%s]", path, k)
+		if n, ok := newByKey[k]; ok {
+			diffValues(elemPath, "", oldByKey[k], n, changes)
+			continue
+		}
+		*changes = append(*changes, PropertyChange{Field: elemPath, Op: ChangeRemoved, Old: oldByKey[k]})
+	}
+	for _, k := range sortedKeys(newByKey) {
+		if _, ok := oldByKey[k]; !ok {
+			*changes = append(*changes, PropertyChange{Field: fmt.Sprintf("%s[%s]", path, k), Op: ChangeAdded, New: newByKey[k]})
+		}
+	}
+}
+
+func indexByKey(list []interface{}, key string) map[string]interface{} {
+	index := make(map[string]interface{}, len(list))
+	for i, elem := range list {
+		k := fmt.Sprint(i)
+		if m, ok := elem.(map[string]interface{}); ok {
+			if v, ok := m[key]; ok {
+				k = fmt.Sprint(v)
+			}
+		}
+		// Duplicate keys are kept apart by position.
+		for _, taken := index[k]; taken; _, taken = index[k] {
+			k += "#"
+		}
+		index[k] = elem
+	}
+	return index
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProperty() *Property {
+	return &Property{
+		HotelID:   1641879,
+		HotelName: "Z Hotel",
+		Rating:    8.3,
+		Address:   Address{City: "London"},
+		Photos: []Photo{
+			{URL: "https://example.com/a.jpg", Score: 4},
+			{URL: "https://example.com/b.jpg", Score: 3},
+		},
+		Facilities: []Facility{{FacilityID: 5, Name: "Wifi"}, {FacilityID: 2, Name: "Bar"}},
+		Rooms: []Room{
+			{ID: 10, RoomName: "Double", MaxAdults: 2},
+		},
+	}
+}
+
+func TestProperty_ContentHash(t *testing.T) {
+	t.Parallel()
+
+	p := testProperty()
+	hash, err := p.ContentHash()
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	reordered := testProperty()
+	reordered.Photos[0], reordered.Photos[1] = reordered.Photos[1], reordered.Photos[0]
+	reordered.Facilities[0], reordered.Facilities[1] = reordered.Facilities[1], reordered.Facilities[0]
+	reorderedHash, err := reordered.ContentHash()
+	require.NoError(t, err)
+	assert.Equal(t, hash, reorderedHash, "list order must not change the hash")
+	assert.Equal(t, "https://example.com/b.jpg", reordered.Photos[0].URL, "hashing must not reorder the caller's slices")
+
+	changed := testProperty()
+	changed.Rating = 8.4
+	changedHash, err := changed.ContentHash()
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestProperty_ContentHash_WiremockProperty(t *testing.T) {
+	t.Parallel()
+
+	_, _, body := wiremockResponse(t, "200.json")
+	first, err := ParseProperty(body)
+	require.NoError(t, err)
+	second, err := ParseProperty(body)
+	require.NoError(t, err)
+
+	firstHash, err := first.ContentHash()
+	require.NoError(t, err)
+	secondHash, err := second.ContentHash()
+	require.NoError(t, err)
+	assert.Equal(t, firstHash, secondHash)
+
+	changes, err := DiffProperties(first, second)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffProperties(t *testing.T) {
+	t.Parallel()
+
+	before := testProperty()
+	after := testProperty()
+	after.Rating = 8.4
+	after.Address.City = "Paris"
+	after.Photos = append(after.Photos,
+		Photo{URL: "https://example.com/c.jpg"},
+		Photo{URL: "https://example.com/d.jpg"},
+	)
+	after.Facilities = after.Facilities[:1]
+	after.Rooms[0].MaxAdults = 3
+
+	changes, err := DiffProperties(before, after)
+	require.NoError(t, err)
+
+	fields := make(map[string]PropertyChange, len(changes))
+	for _, c := range changes {
+		fields[c.Field] = c
+	}
+
+	require.Contains(t, fields, "rating")
+	assert.Equal(t, ChangeModified, fields["rating"].Op)
+	assert.Equal(t, json.Number("8.3"), fields["rating"].Old)
+	assert.Equal(t, json.Number("8.4"), fields["rating"].New)
+
+	assert.Equal(t, ChangeModified, fields["address.city"].Op)
+	assert.Equal(t, ChangeAdded, fields["photos[https://example.com/c.jpg]"].Op)
+	assert.Equal(t, ChangeAdded, fields["photos[https://example.com/d.jpg]"].Op)
+	assert.Equal(t, ChangeRemoved, fields["facilities[2]"].Op)
+	assert.Equal(t, ChangeModified, fields["rooms[10].max_adults"].Op)
+	assert.Len(t, changes, 6)
+
+	assert.Equal(t,
+		`address.city "London"→"Paris"; rating 8.3→8.4; 1 facilities removed; 2 photos added; 1 rooms changed`,
+		SummarizeChanges(changes))
+}
+
+func TestDiffProperties_NoChanges(t *testing.T) {
+	t.Parallel()
+
+	changes, err := DiffProperties(testProperty(), testProperty())
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Empty(t, SummarizeChanges(changes))
+}
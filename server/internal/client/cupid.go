@@ -1,14 +1,19 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // / Client is an HTTP client for Cupid
@@ -16,7 +21,17 @@ type Client struct {
 	baseURL    *url.URL
 	httpClient *http.Client
 	userAgent  string
+	apiKey     string
 	forceClose bool
+
+	retry           RetryPolicy
+	breakerSettings *BreakerSettings
+	breakersMu      sync.Mutex
+	breakers        map[string]*circuitBreaker
+	validators      ValidatorStore
+	archive         PayloadArchive
+	limiter         *rate.Limiter
+	budget          *RequestBudget
 }
 
 // Option configures the Client.
@@ -36,6 +51,11 @@ func WithUserAgent(ua string) Option {
 	return func(c *Client) { c.userAgent = ua }
 }
 
+// WithAPIKey sets the x-api-key header sent by the typed Cupid API methods.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
 // WithTimeout configures timeout on the underlying *http.Client.
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Client) {
@@ -75,30 +95,97 @@ func New(baseURL string, opts ...Option) (*Client, error) {
 	return c, nil
 }
 
-// Error represents 4xx or 5xx HTTP responses.
-type Error struct {
-	StatusCode int
-	RequestID  string
+// Do issues an HTTP request and returns the response body for 2xx codes.
+// For 4xx/5xx, it returns a typed error containing status and request id.
+// A 304 answer to a conditional request is returned as ErrNotModified.
+// Transient failures are retried according to the configured RetryPolicy and
+// requests are rejected with ErrCircuitOpen while the host's breaker is open.
+func (c *Client) Do(ctx context.Context, method, path string, body io.Reader, headers http.Header) ([]byte, *http.Response, error) {
+	fullURL := c.resolveURL(path)
+
+	// Buffer the request body so it can be replayed on retries.
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	host := fullURL
+	if u, err := url.Parse(fullURL); err == nil {
+		host = u.Host
+	}
+	breaker := c.breakerFor(host)
+	headers = c.conditionalHeaders(ctx, method, fullURL, headers)
+
+	for attempt := 1; ; attempt++ {
+		if breaker != nil {
+			if err := breaker.allow(); err != nil {
+				return nil, nil, fmt.Errorf("%s %s: %w", method, host, err)
+			}
+		}
+
+		if err := c.throttle(ctx); err != nil {
+			if breaker != nil {
+				breaker.release()
+			}
+			return nil, nil, err
+		}
+
+		respBody, resp, err := c.doOnce(ctx, method, fullURL, payload, headers)
+
+		reason := c.retry.retryReason(resp, err)
+		if breaker != nil {
+			breaker.recordOutcome(ctx, resp, err)
+		}
+		if reason == "" || attempt >= c.retry.MaxAttempts {
+			return respBody, resp, err
+		}
+
+		delay := c.retry.backoff(attempt, resp)
+		log.Printf("retrying %s %s in %v (attempt %d/%d, reason %s)", method, fullURL, delay, attempt+1, c.retry.MaxAttempts, reason)
+		metrics.recordRetry(host, reason)
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return nil, resp, sleepErr
+		}
+	}
 }
 
-func (e *Error) Error() string {
-	return fmt.Sprintf("error: status=%d request_id=%s", e.StatusCode, e.RequestID)
+// throttle spends one request from the budget and waits for the rate limiter.
+func (c *Client) throttle(ctx context.Context) error {
+	if c.budget != nil {
+		if err := c.budget.take(); err != nil {
+			return err
+		}
+	}
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limiter: %w", err)
+		}
+	}
+	return nil
 }
 
-// Do issues an HTTP request and returns the response body for 2xx codes.
-// For 4xx/5xx, it returns a typed error containing status and request id.
-func (c *Client) Do(ctx context.Context, method, path string, body io.Reader, headers http.Header) ([]byte, *http.Response, error) {
+func (c *Client) resolveURL(path string) string {
 	// Compose URL with minimal assumptions. Caller is responsible for correct path.
-	fullURL := path
-	if !strings.HasPrefix(path, "http://") && !strings.HasPrefix(path, "https://") {
-		// naive join: base + path
-		base := strings.TrimRight(c.baseURL.String(), "/")
-		if !strings.HasPrefix(path, "/") {
-			path = "/" + path
-		}
-		fullURL = base + path
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
 	}
+	// naive join: base + path
+	base := strings.TrimRight(c.baseURL.String(), "/")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return base + path
+}
 
+// doOnce performs a single HTTP round trip.
+func (c *Client) doOnce(ctx context.Context, method, fullURL string, payload []byte, headers http.Header) ([]byte, *http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
 	if err != nil {
 		return nil, nil, err
@@ -126,17 +213,21 @@ func (c *Client) Do(ctx context.Context, method, path string, body io.Reader, he
 	if err != nil {
 		return nil, resp, err
 	}
-
-	requestID := resp.Header.Get("X-Request-Id")
+	if err := c.archivePayload(ctx, method, fullURL, resp, respBody); err != nil {
+		log.Printf("Warning: failed to archive payload of %s: %v", fullURL, err)
+	}
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if err := c.saveValidators(ctx, method, fullURL, resp); err != nil {
+			log.Printf("Warning: failed to save validators for %s: %v", fullURL, err)
+		}
 		return respBody, resp, nil
 	}
-	if resp.StatusCode >= 400 && resp.StatusCode <= 499 {
-		return nil, resp, &Error{StatusCode: resp.StatusCode, RequestID: requestID}
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, resp, ErrNotModified
 	}
-	if resp.StatusCode >= 500 && resp.StatusCode <= 599 {
-		return nil, resp, &Error{StatusCode: resp.StatusCode, RequestID: requestID}
+	if resp.StatusCode >= 400 && resp.StatusCode <= 599 {
+		return nil, resp, newError(method, fullURL, resp, respBody)
 	}
 	return nil, resp, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 }
@@ -85,3 +85,102 @@ func TestClient_Do_StatusMapping(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetProperty(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3.0/property/1641879", r.URL.Path)
+		assert.Equal(t, "secret-key", r.Header.Get("x-api-key"))
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		assert.Equal(t, "cupid-agent", r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"hotel_id":1641879,"hotel_name":"The Z Hotel","rating":8.3}`))
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithAPIKey("secret-key"), WithUserAgent("cupid-agent"))
+	assert.NoError(t, err)
+
+	property, err := c.GetProperty(context.Background(), 1641879)
+	assert.NoError(t, err)
+	if assert.NotNil(t, property) {
+		assert.Equal(t, 1641879, property.HotelID)
+		assert.Equal(t, "The Z Hotel", property.HotelName)
+		assert.Equal(t, 8.3, property.Rating)
+	}
+}
+
+func TestClient_GetProperty_Error(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(&mockCupidAPI{
+		statusCode: http.StatusInternalServerError,
+		body:       `{"error":"server"}`,
+		headers:    map[string]string{"X-Request-Id": "wm-500"},
+	})
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	assert.NoError(t, err)
+
+	property, err := c.GetProperty(context.Background(), 1)
+	assert.Nil(t, property)
+	var ce *Error
+	if assert.True(t, errors.As(err, &ce)) {
+		assert.Equal(t, http.StatusInternalServerError, ce.StatusCode)
+		assert.Equal(t, "wm-500", ce.RequestID)
+	}
+}
+
+func TestClient_GetReviews(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		body      string
+		wantCount int
+	}{
+		{"two_reviews", `[{"id":1,"rating":5},{"id":2,"rating":4}]`, 2},
+		{"empty_body", ``, 0},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v3.0/property/reviews/42/100", r.URL.Path)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer ts.Close()
+
+			c, err := New(ts.URL)
+			assert.NoError(t, err)
+
+			reviews, err := c.GetReviews(context.Background(), 42, 100)
+			assert.NoError(t, err)
+			assert.Len(t, reviews, tc.wantCount)
+		})
+	}
+}
+
+func TestClient_GetTranslations(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v3.0/property/42/lang/fr", r.URL.Path)
+		_, _ = w.Write([]byte(`[{"field_name":"hotel_name","translated_text":"Hôtel Z"}]`))
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	assert.NoError(t, err)
+
+	translations, err := c.GetTranslations(context.Background(), 42, "fr")
+	assert.NoError(t, err)
+	if assert.Len(t, translations, 1) {
+		assert.Equal(t, "fr", translations[0].LanguageCode)
+		assert.Equal(t, "Hôtel Z", translations[0].TranslatedText)
+	}
+}
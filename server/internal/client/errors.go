@@ -0,0 +1,160 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxErrorBodySize bounds the raw response body kept on an Error.
+const maxErrorBodySize = 1024
+
+// Sentinel errors matched by Error through errors.Is.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrRetryable    = errors.New("retryable")
+)
+
+// Error represents 4xx or 5xx HTTP responses.
+type Error struct {
+	StatusCode int
+	RequestID  string
+	Method     string
+	URL        string
+
+	// Code, Message and Details are decoded from the Cupid error payload when present.
+	Code    string
+	Message string
+	Details json.RawMessage
+
+	// Body is the raw response body, truncated to maxErrorBodySize bytes.
+	Body string
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Method != "" || e.URL != "" {
+		fmt.Fprintf(&b, "%s %s: ", e.Method, e.URL)
+	}
+	fmt.Fprintf(&b, "error: status=%d request_id=%s", e.StatusCode, e.RequestID)
+	if e.Code != "" {
+		fmt.Fprintf(&b, " code=%s", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, " message=%q", e.Message)
+	}
+	return b.String()
+}
+
+// Is lets callers match an Error against the package sentinel errors.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrRetryable:
+		switch e.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+			http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err is a 404 from the Cupid API.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsRateLimited reports whether err is a 429 from the Cupid API.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying later:
+// timeouts, rate limiting, 5xx responses and dropped connections.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return errors.Is(apiErr, ErrRetryable)
+	}
+	return isConnectionReset(err)
+}
+
+// newError builds an Error from a failed response and its body.
+func newError(method, url string, resp *http.Response, body []byte) *Error {
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+		Method:     method,
+		URL:        url,
+		Body:       truncate(string(body), maxErrorBodySize),
+	}
+	e.Code, e.Message, e.Details = decodeErrorPayload(body)
+	return e
+}
+
+// decodeErrorPayload extracts code, message and details from a Cupid error body.
+// It accepts both the flat form {"code":..,"message":..,"details":..} and the
+// nested form {"error":{...}}, as well as a bare {"error":"message"}.
+func decodeErrorPayload(body []byte) (code, message string, details json.RawMessage) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", "", nil
+	}
+
+	if raw, ok := fields["error"]; ok {
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			message = text
+		} else {
+			code, message, details = decodeErrorPayload(raw)
+		}
+	}
+
+	if raw, ok := fields["code"]; ok {
+		code = jsonScalar(raw)
+	}
+	for _, key := range []string{"message", "detail", "description"} {
+		if raw, ok := fields[key]; ok {
+			message = jsonScalar(raw)
+			break
+		}
+	}
+	if raw, ok := fields["details"]; ok {
+		details = raw
+	}
+	return code, message, details
+}
+
+// jsonScalar renders a JSON string or number as plain text.
+func jsonScalar(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number.String()
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + "...(truncated " + strconv.Itoa(len(s)-limit) + " bytes)"
+}
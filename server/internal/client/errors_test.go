@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wiremockResponse loads the response of a mapping in wiremock/mappings.
+func wiremockResponse(t *testing.T, name string) (int, map[string]string, []byte) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "wiremock", "mappings", name))
+	require.NoError(t, err)
+
+	var mapping struct {
+		Response struct {
+			Status   int               `json:"status"`
+			Headers  map[string]string `json:"headers"`
+			JSONBody json.RawMessage   `json:"jsonBody"`
+		} `json:"response"`
+	}
+	require.NoError(t, json.Unmarshal(data, &mapping))
+	return mapping.Response.Status, mapping.Response.Headers, mapping.Response.JSONBody
+}
+
+func TestClient_Do_DecodesWiremockErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mapping       string
+		wantMessage   string
+		wantRequestID string
+		wantRetryable bool
+	}{
+		{"400.json", "client", "wm-400", false},
+		{"500.json", "server", "wm-500", true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.mapping, func(t *testing.T) {
+			t.Parallel()
+
+			status, headers, body := wiremockResponse(t, tc.mapping)
+			ts := httptest.NewServer(&mockCupidAPI{statusCode: status, body: string(body), headers: headers})
+			defer ts.Close()
+
+			c, err := New(ts.URL)
+			require.NoError(t, err)
+
+			_, _, err = c.Do(context.Background(), http.MethodGet, "/v3.0/property/bad-1", nil, nil)
+			var ce *Error
+			require.True(t, errors.As(err, &ce), "expected client.Error, got %T: %v", err, err)
+			assert.Equal(t, status, ce.StatusCode)
+			assert.Equal(t, tc.wantRequestID, ce.RequestID)
+			assert.Equal(t, tc.wantMessage, ce.Message)
+			assert.Equal(t, http.MethodGet, ce.Method)
+			assert.Equal(t, ts.URL+"/v3.0/property/bad-1", ce.URL)
+			assert.JSONEq(t, string(body), ce.Body)
+			assert.Equal(t, tc.wantRetryable, IsRetryable(err))
+			assert.Contains(t, err.Error(), "GET "+ts.URL)
+		})
+	}
+}
+
+func TestDecodeErrorPayload(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		body        string
+		wantCode    string
+		wantMessage string
+		wantDetails string
+	}{
+		{"bare_error", `{"error":"client"}`, "", "client", ""},
+		{"flat", `{"code":"HOTEL_NOT_FOUND","message":"no such hotel","details":{"hotel_id":1}}`, "HOTEL_NOT_FOUND", "no such hotel", `{"hotel_id":1}`},
+		{"nested", `{"error":{"code":404,"message":"missing","details":["a"]}}`, "404", "missing", `["a"]`},
+		{"not_json", `<html>oops</html>`, "", "", ""},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			code, message, details := decodeErrorPayload([]byte(tc.body))
+			assert.Equal(t, tc.wantCode, code)
+			assert.Equal(t, tc.wantMessage, message)
+			assert.Equal(t, tc.wantDetails, string(details))
+		})
+	}
+}
+
+func TestError_IsHelpers(t *testing.T) {
+	t.Parallel()
+
+	notFound := fmt.Errorf("wrapped: %w", &Error{StatusCode: http.StatusNotFound})
+	rateLimited := &Error{StatusCode: http.StatusTooManyRequests}
+	unavailable := &Error{StatusCode: http.StatusServiceUnavailable}
+
+	assert.True(t, IsNotFound(notFound))
+	assert.False(t, IsRetryable(notFound))
+	assert.True(t, IsRateLimited(rateLimited))
+	assert.True(t, IsRetryable(rateLimited))
+	assert.False(t, IsNotFound(unavailable))
+	assert.True(t, IsRetryable(unavailable))
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(errors.New("boom")))
+}
+
+func TestNewError_TruncatesBody(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(strings.Repeat("x", maxErrorBodySize+10))
+	e := newError(http.MethodGet, "http://example.com", &http.Response{StatusCode: 502, Header: http.Header{}}, body)
+	assert.True(t, strings.HasPrefix(e.Body, strings.Repeat("x", maxErrorBodySize)))
+	assert.Contains(t, e.Body, "truncated 10 bytes")
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "github.com/vrnvu/cupid/internal/client"
+
+// clientMetrics holds the OpenTelemetry instruments reported by the client.
+// Instruments come from the global meter provider, so they are no-ops until
+// telemetry.ConfigureOpenTelemetry has been called.
+type clientMetrics struct {
+	retries            metric.Int64Counter
+	breakerTransitions metric.Int64Counter
+	breakerRejections  metric.Int64Counter
+}
+
+var metrics = newClientMetrics()
+
+func newClientMetrics() *clientMetrics {
+	meter := otel.Meter(instrumentationName)
+	m := &clientMetrics{}
+	m.retries, _ = meter.Int64Counter("cupid.client.retries",
+		metric.WithDescription("Number of retried Cupid API requests"))
+	m.breakerTransitions, _ = meter.Int64Counter("cupid.client.circuit_breaker.transitions",
+		metric.WithDescription("Number of circuit breaker state changes"))
+	m.breakerRejections, _ = meter.Int64Counter("cupid.client.circuit_breaker.rejections",
+		metric.WithDescription("Number of requests rejected by an open circuit breaker"))
+	return m
+}
+
+func (m *clientMetrics) recordRetry(host, reason string) {
+	if m.retries == nil {
+		return
+	}
+	m.retries.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("host", host),
+		attribute.String("reason", reason),
+	))
+}
+
+func (m *clientMetrics) recordTransition(host string, from, to BreakerState) {
+	if m.breakerTransitions == nil {
+		return
+	}
+	m.breakerTransitions.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("host", host),
+		attribute.String("from", from.String()),
+		attribute.String("to", to.String()),
+	))
+}
+
+func (m *clientMetrics) recordRejection(host string) {
+	if m.breakerRejections == nil {
+		return
+	}
+	m.breakerRejections.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("host", host),
+	))
+}
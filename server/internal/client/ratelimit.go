@@ -0,0 +1,89 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrBudgetExhausted is matched by BudgetExhaustedError through errors.Is.
+var ErrBudgetExhausted = errors.New("request budget exhausted")
+
+// BudgetExhaustedError is returned by Do once the daily request budget is spent.
+// No request is sent to the upstream API when it is returned.
+type BudgetExhaustedError struct {
+	Limit   int
+	ResetAt time.Time
+}
+
+func (e *BudgetExhaustedError) Error() string {
+	return fmt.Sprintf("%v: limit=%d reset_at=%s", ErrBudgetExhausted, e.Limit, e.ResetAt.Format(time.RFC3339))
+}
+
+// Is matches ErrBudgetExhausted.
+func (e *BudgetExhaustedError) Is(target error) bool {
+	return target == ErrBudgetExhausted
+}
+
+// RequestBudget caps the number of requests sent per UTC day.
+// It is safe for concurrent use and can be shared between clients.
+type RequestBudget struct {
+	limit int
+	now   func() time.Time
+
+	mu   sync.Mutex
+	day  time.Time
+	used int
+}
+
+// NewDailyBudget returns a budget of limit requests per UTC day.
+func NewDailyBudget(limit int) *RequestBudget {
+	return &RequestBudget{limit: limit, now: time.Now}
+}
+
+// take consumes one request from the budget.
+func (b *RequestBudget) take() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	today := b.now().UTC().Truncate(24 * time.Hour)
+	if !today.Equal(b.day) {
+		b.day = today
+		b.used = 0
+	}
+	if b.used >= b.limit {
+		return &BudgetExhaustedError{Limit: b.limit, ResetAt: today.Add(24 * time.Hour)}
+	}
+	b.used++
+	return nil
+}
+
+// Remaining returns the number of requests left for the current day.
+func (b *RequestBudget) Remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.now().UTC().Truncate(24 * time.Hour).Equal(b.day) {
+		return b.limit
+	}
+	return b.limit - b.used
+}
+
+// WithRateLimiter throttles every outgoing request, retries included, through limiter.
+// Share one limiter between clients to enforce a global rate.
+func WithRateLimiter(limiter *rate.Limiter) Option {
+	return func(c *Client) { c.limiter = limiter }
+}
+
+// WithRateLimit throttles outgoing requests to rps requests per second with the given burst.
+func WithRateLimit(rps float64, burst int) Option {
+	return WithRateLimiter(rate.NewLimiter(rate.Limit(rps), burst))
+}
+
+// WithRequestBudget caps the number of requests per day. Once exhausted, Do
+// returns a *BudgetExhaustedError without contacting the upstream API.
+func WithRequestBudget(budget *RequestBudget) Option {
+	return func(c *Client) { c.budget = budget }
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Do_RequestBudget(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer ts.Close()
+
+	budget := NewDailyBudget(2)
+	c, err := New(ts.URL, WithRequestBudget(budget))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetReviews(context.Background(), 1, 10)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 0, budget.Remaining())
+
+	_, err = c.GetReviews(context.Background(), 1, 10)
+	assert.ErrorIs(t, err, ErrBudgetExhausted)
+	var budgetErr *BudgetExhaustedError
+	if assert.True(t, errors.As(err, &budgetErr)) {
+		assert.Equal(t, 2, budgetErr.Limit)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRequestBudget_ResetsDaily(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 9, 1, 23, 59, 0, 0, time.UTC)
+	budget := NewDailyBudget(1)
+	budget.now = func() time.Time { return now }
+
+	require.NoError(t, budget.take())
+	var budgetErr *BudgetExhaustedError
+	require.True(t, errors.As(budget.take(), &budgetErr))
+	assert.Equal(t, time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC), budgetErr.ResetAt)
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, 1, budget.Remaining())
+	assert.NoError(t, budget.take())
+}
+
+func TestClient_Do_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithRateLimit(20, 1))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, _, err := c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+		require.NoError(t, err)
+	}
+	// Burst of 1 at 20 rps: the 2nd and 3rd requests wait ~50ms each.
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestClient_Do_RateLimitHonoursContext(t *testing.T) {
+	t.Parallel()
+
+	c, err := New("http://127.0.0.1:1", WithRateLimit(0.001, 1))
+	require.NoError(t, err)
+	c.limiter.Allow() // drain the only token
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = c.Do(ctx, http.MethodGet, "/path", nil, nil)
+	assert.Error(t, err)
+}
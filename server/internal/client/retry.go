@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how Do retries failed requests.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry. It doubles on every attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff. It does not cap Retry-After.
+	MaxDelay time.Duration
+	// RetryableStatus lists the HTTP status codes that are worth retrying.
+	RetryableStatus []int
+}
+
+// DefaultRetryPolicy returns a policy with 4 attempts and jittered exponential
+// backoff between 200ms and 5s, retrying 429, 502, 503 and 504 responses.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		RetryableStatus: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// WithRetryPolicy enables retries on transient failures.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// retryReason classifies a response or transport error. It returns an empty
+// string when the outcome must not be retried.
+func (p RetryPolicy) retryReason(resp *http.Response, err error) string {
+	var apiErr *Error
+	if err != nil && !errors.As(err, &apiErr) && isConnectionReset(err) {
+		return "connection_reset"
+	}
+	if resp == nil {
+		return ""
+	}
+	for _, status := range p.RetryableStatus {
+		if resp.StatusCode == status {
+			return strconv.Itoa(status)
+		}
+	}
+	return ""
+}
+
+// backoff returns how long to wait before the given retry attempt (1-based).
+// A Retry-After header on the response takes precedence over the computed delay.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return delay
+		}
+	}
+
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	// Full jitter spreads retries from concurrent callers.
+	return rand.N(delay) + 1
+}
+
+// parseRetryAfter parses a Retry-After header in either delay-seconds or HTTP-date form.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		delay := at.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// isConnectionReset reports whether err is a connection dropped by the peer.
+func isConnectionReset(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
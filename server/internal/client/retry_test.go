@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fastRetryPolicy() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	p.MaxDelay = 5 * time.Millisecond
+	return p
+}
+
+func TestClient_Do_RetriesTransientStatus(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithRetryPolicy(fastRetryPolicy()))
+	assert.NoError(t, err)
+
+	body, _, err := c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(body))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_Do_DoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithRetryPolicy(fastRetryPolicy()))
+	assert.NoError(t, err)
+
+	_, _, err = c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+	var ce *Error
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_Do_GivesUpAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	policy := fastRetryPolicy()
+	policy.MaxAttempts = 3
+	c, err := New(ts.URL, WithRetryPolicy(policy))
+	assert.NoError(t, err)
+
+	_, _, err = c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+	var ce *Error
+	if assert.True(t, errors.As(err, &ce)) {
+		assert.Equal(t, http.StatusBadGateway, ce.StatusCode)
+	}
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_Do_RetriesConnectionReset(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		_, _ = w.Write([]byte(`ok`))
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL, WithRetryPolicy(fastRetryPolicy()))
+	assert.NoError(t, err)
+
+	body, _, err := c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryPolicy_BackoffHonoursRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	assert.Equal(t, 7*time.Second, DefaultRetryPolicy().backoff(1, resp))
+}
+
+func TestRetryPolicy_BackoffIsCapped(t *testing.T) {
+	t.Parallel()
+
+	policy := DefaultRetryPolicy()
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay := policy.backoff(attempt, nil)
+		assert.Greater(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, policy.MaxDelay+1)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"seconds", "3", 3 * time.Second, true},
+		{"http_date", now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second, true},
+		{"past_date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"empty", "", 0, false},
+		{"garbage", "soon", 0, false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := parseRetryAfter(tc.value, now)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists the problems found in a payload.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid payload: " + strings.Join(e.Problems, "; ")
+}
+
+type validator struct {
+	problems []string
+}
+
+func (v *validator) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.problems = append(v.problems, fmt.Sprintf(format, args...))
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
+
+// Validate checks that a property can be stored: identifiers are set,
+// required names are present and values fit the database schema.
+func (p *Property) Validate() error {
+	v := &validator{}
+	v.check(p.HotelID > 0, "hotel_id must be positive")
+	v.check(strings.TrimSpace(p.HotelName) != "", "hotel_name is required")
+	v.check(len(p.HotelName) <= 500, "hotel_name is longer than 500 characters")
+	v.check(p.Latitude >= -90 && p.Latitude <= 90, "latitude %v out of range", p.Latitude)
+	v.check(p.Longitude >= -180 && p.Longitude <= 180, "longitude %v out of range", p.Longitude)
+	v.check(p.Stars >= 0 && p.Stars <= 5, "stars %d out of range 0-5", p.Stars)
+	v.check(p.Rating >= 0 && p.Rating <= 10, "rating %v out of range 0-10", p.Rating)
+	v.check(p.ReviewCount >= 0, "review_count must not be negative")
+
+	for i, photo := range p.Photos {
+		v.check(photo.URL != "", "photos[%d].url is required", i)
+	}
+	for i, facility := range p.Facilities {
+		v.check(facility.Name != "", "facilities[%d].name is required", i)
+	}
+	for i, policy := range p.Policies {
+		v.check(policy.Name != "", "policies[%d].name is required", i)
+	}
+	for i, room := range p.Rooms {
+		v.check(room.ID > 0, "rooms[%d].id must be positive", i)
+		v.check(room.RoomName != "", "rooms[%d].room_name is required", i)
+		for j, bed := range room.BedTypes {
+			v.check(bed.BedType != "", "rooms[%d].bed_types[%d].bed_type is required", i, j)
+		}
+		for j, amenity := range room.RoomAmenities {
+			v.check(amenity.Name != "", "rooms[%d].room_amenities[%d].name is required", i, j)
+		}
+	}
+	return v.err()
+}
+
+// Validate checks that a review can be stored.
+func (r Review) Validate() error {
+	v := &validator{}
+	v.check(r.Rating >= 1 && r.Rating <= 5, "rating %d out of range 1-5", r.Rating)
+	v.check(len(r.ReviewerName) <= 255, "reviewer_name is longer than 255 characters")
+	v.check(len(r.Title) <= 500, "title is longer than 500 characters")
+	v.check(len(r.LanguageCode) <= 10, "language_code %q is longer than 10 characters", r.LanguageCode)
+	return v.err()
+}
+
+// Validate checks that a translation can be stored.
+func (t Translation) Validate() error {
+	v := &validator{}
+	v.check(t.LanguageCode != "", "language_code is required")
+	v.check(len(t.LanguageCode) <= 10, "language_code %q is longer than 10 characters", t.LanguageCode)
+	v.check(t.FieldName != "", "field_name is required")
+	v.check(len(t.FieldName) <= 100, "field_name is longer than 100 characters")
+	v.check(t.TranslatedText != "", "translated_text is required")
+	return v.err()
+}
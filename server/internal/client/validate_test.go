@@ -0,0 +1,81 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProperty_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("wiremock fixture is valid", func(t *testing.T) {
+		t.Parallel()
+		_, _, body := wiremockResponse(t, "200.json")
+		property, err := ParseProperty(body)
+		require.NoError(t, err)
+		assert.NoError(t, property.Validate())
+	})
+
+	t.Run("reports every problem", func(t *testing.T) {
+		t.Parallel()
+		property := &Property{
+			Latitude: 91,
+			Stars:    6,
+			Photos:   []Photo{{URL: ""}},
+			Rooms:    []Room{{ID: 1, RoomName: "Double", BedTypes: []BedType{{}}}},
+		}
+
+		err := property.Validate()
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.ElementsMatch(t, []string{
+			"hotel_id must be positive",
+			"hotel_name is required",
+			"latitude 91 out of range",
+			"stars 6 out of range 0-5",
+			"photos[0].url is required",
+			"rooms[0].bed_types[0].bed_type is required",
+		}, validationErr.Problems)
+	})
+}
+
+func TestReview_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		review  Review
+		wantErr bool
+	}{
+		{"valid", Review{Rating: 4, LanguageCode: "en"}, false},
+		{"rating too low", Review{Rating: 0}, true},
+		{"rating too high", Review{Rating: 6}, true},
+		{"language code too long", Review{Rating: 3, LanguageCode: "not-a-language"}, true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.review.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTranslation_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, Translation{LanguageCode: "fr", FieldName: "description", TranslatedText: "Bonjour"}.Validate())
+
+	err := Translation{LanguageCode: "fr"}.Validate()
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{"field_name is required", "translated_text is required"}, validationErr.Problems)
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotModified is returned by Do when the server answered 304 Not Modified
+// to a conditional request, meaning the resource is unchanged since the last fetch.
+var ErrNotModified = errors.New("not modified")
+
+// Validators are the HTTP cache validators last seen for a URL.
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// IsZero reports whether no validator is set.
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// ValidatorStore persists validators per URL so GET requests can be made conditional.
+type ValidatorStore interface {
+	GetValidators(ctx context.Context, url string) (Validators, bool, error)
+	SetValidators(ctx context.Context, url string, v Validators) error
+	DeleteValidators(ctx context.Context, url string) error
+}
+
+// WithValidatorStore enables conditional GET requests. Validators returned by
+// successful responses are saved to store and sent back as If-None-Match and
+// If-Modified-Since; a 304 answer surfaces as ErrNotModified.
+func WithValidatorStore(store ValidatorStore) Option {
+	return func(c *Client) { c.validators = store }
+}
+
+// ForgetValidators drops the stored validators for path so the next request
+// downloads the full payload. Callers use it when a fetched payload could not be
+// persisted and must not be reported as unchanged next time.
+func (c *Client) ForgetValidators(ctx context.Context, path string) error {
+	if c.validators == nil {
+		return nil
+	}
+	return c.validators.DeleteValidators(ctx, c.resolveURL(path))
+}
+
+// conditionalHeaders adds the stored validators for fullURL to headers.
+func (c *Client) conditionalHeaders(ctx context.Context, method, fullURL string, headers http.Header) http.Header {
+	if c.validators == nil || method != http.MethodGet {
+		return headers
+	}
+	v, ok, err := c.validators.GetValidators(ctx, fullURL)
+	if err != nil || !ok || v.IsZero() {
+		return headers
+	}
+
+	conditional := headers.Clone()
+	if conditional == nil {
+		conditional = http.Header{}
+	}
+	if v.ETag != "" {
+		conditional.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		conditional.Set("If-Modified-Since", v.LastModified)
+	}
+	return conditional
+}
+
+// saveValidators records the validators of a successful GET response.
+func (c *Client) saveValidators(ctx context.Context, method, fullURL string, resp *http.Response) error {
+	if c.validators == nil || method != http.MethodGet {
+		return nil
+	}
+	v := Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if v.IsZero() {
+		return c.validators.DeleteValidators(ctx, fullURL)
+	}
+	return c.validators.SetValidators(ctx, fullURL, v)
+}
+
+// FileValidatorStore keeps validators on disk, one JSON file per URL.
+type FileValidatorStore struct {
+	dir string
+}
+
+// NewFileValidatorStore creates a store rooted at dir, creating it if needed.
+func NewFileValidatorStore(dir string) (*FileValidatorStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create validator dir: %w", err)
+	}
+	return &FileValidatorStore{dir: dir}, nil
+}
+
+func (s *FileValidatorStore) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// GetValidators implements ValidatorStore.
+func (s *FileValidatorStore) GetValidators(_ context.Context, url string) (Validators, bool, error) {
+	data, err := os.ReadFile(s.path(url))
+	if errors.Is(err, os.ErrNotExist) {
+		return Validators{}, false, nil
+	}
+	if err != nil {
+		return Validators{}, false, err
+	}
+	var v Validators
+	if err := json.Unmarshal(data, &v); err != nil {
+		return Validators{}, false, fmt.Errorf("failed to decode validators: %w", err)
+	}
+	return v, true, nil
+}
+
+// SetValidators implements ValidatorStore.
+func (s *FileValidatorStore) SetValidators(_ context.Context, url string, v Validators) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// Write to a temp file and rename so concurrent readers never see partial data.
+	tmp, err := os.CreateTemp(s.dir, "validators-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(url))
+}
+
+// DeleteValidators implements ValidatorStore.
+func (s *FileValidatorStore) DeleteValidators(_ context.Context, url string) error {
+	err := os.Remove(s.path(url))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
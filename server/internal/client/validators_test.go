@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetProperty_ConditionalRequest(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			assert.Equal(t, "Mon, 01 Sep 2025 10:00:00 GMT", r.Header.Get("If-Modified-Since"))
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Sep 2025 10:00:00 GMT")
+		_, _ = w.Write([]byte(`{"hotel_id":1}`))
+	}))
+	defer ts.Close()
+
+	store, err := NewFileValidatorStore(t.TempDir())
+	require.NoError(t, err)
+	c, err := New(ts.URL, WithValidatorStore(store))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	property, err := c.GetProperty(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, property.HotelID)
+
+	property, err = c.GetProperty(ctx, 1)
+	assert.ErrorIs(t, err, ErrNotModified)
+	assert.Nil(t, property)
+
+	require.NoError(t, c.ForgetValidators(ctx, PropertyPath(1)))
+	property, err = c.GetProperty(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, property.HotelID)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_Do_WithoutValidatorStoreIsUnconditional(t *testing.T) {
+	t.Parallel()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, _, err = c.Do(context.Background(), http.MethodGet, "/path", nil, nil)
+		assert.NoError(t, err)
+	}
+}
+
+func TestFileValidatorStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewFileValidatorStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	url := "https://content-api.cupid.travel/v3.0/property/1"
+
+	_, ok, err := store.GetValidators(ctx, url)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	want := Validators{ETag: `"abc"`, LastModified: "Mon, 01 Sep 2025 10:00:00 GMT"}
+	require.NoError(t, store.SetValidators(ctx, url, want))
+
+	got, ok, err := store.GetValidators(ctx, url)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, want, got)
+
+	require.NoError(t, store.DeleteValidators(ctx, url))
+	require.NoError(t, store.DeleteValidators(ctx, url))
+	_, ok, err = store.GetValidators(ctx, url)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,22 @@
+package compress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGzip_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"hotel_id":1}`)
+	compressed, err := Gzip(data)
+	require.NoError(t, err)
+	decompressed, err := Gunzip(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, data, decompressed)
+
+	_, err = Gunzip(data)
+	assert.Error(t, err)
+}
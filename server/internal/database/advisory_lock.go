@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// AdvisoryLock is a session-level Postgres advisory lock. It is held on a
+// dedicated connection; Postgres also releases it when the holding session
+// ends, so a crashed holder never keeps it.
+type AdvisoryLock struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// TryAdvisoryLock takes the advisory lock key without waiting. It returns a
+// nil lock when another session holds it.
+func (db *DB) TryAdvisoryLock(ctx context.Context, key int64) (*AdvisoryLock, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, nil
+	}
+	return &AdvisoryLock{conn: conn, key: key}, nil
+}
+
+// AdvisoryLock takes the advisory lock key, waiting until it is free or ctx
+// is done.
+func (db *DB) AdvisoryLock(ctx context.Context, key int64) (*AdvisoryLock, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, key); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire advisory lock: %w", err)
+	}
+	return &AdvisoryLock{conn: conn, key: key}, nil
+}
+
+// Ping checks that the connection holding the lock, and so the lock, is still alive.
+func (l *AdvisoryLock) Ping(ctx context.Context) error {
+	return l.conn.PingContext(ctx)
+}
+
+// Release unlocks the lock and returns its connection to the pool.
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	defer l.conn.Close()
+	if _, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, l.key); err != nil {
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+	return nil
+}
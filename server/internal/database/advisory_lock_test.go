@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTryAdvisoryLock(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	ctx := context.Background()
+	key := int64(randomID())
+
+	lock, err := db.TryAdvisoryLock(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.NoError(t, lock.Ping(ctx))
+
+	other, err := db.TryAdvisoryLock(ctx, key)
+	require.NoError(t, err)
+	assert.Nil(t, other, "lock must not be granted to a second session")
+
+	require.NoError(t, lock.Release(ctx))
+
+	again, err := db.TryAdvisoryLock(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, again, "lock must be available after release")
+	require.NoError(t, again.Release(ctx))
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCatalogEntryNotFound is returned when a hotel is not tracked in the catalog.
+var ErrCatalogEntryNotFound = errors.New("catalog entry not found")
+
+// CatalogEntry is a hotel tracked by the sync jobs.
+type CatalogEntry struct {
+	HotelID   int       `json:"hotel_id"`
+	Active    bool      `json:"active"`
+	Priority  int       `json:"priority"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetCatalogHotelIDs returns the tracked hotel IDs, highest priority first.
+// With activeOnly set, inactive hotels are left out.
+func (r *HotelRepository) GetCatalogHotelIDs(ctx context.Context, activeOnly bool) ([]int, error) {
+	query := `
+		SELECT hotel_id FROM hotel_catalog
+		WHERE active OR NOT $1
+		ORDER BY priority DESC, hotel_id`
+
+	rows, err := r.db.QueryContext(ctx, query, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hotel catalog: %w", err)
+	}
+	defer rows.Close()
+
+	var hotelIDs []int
+	for rows.Next() {
+		var hotelID int
+		if err := rows.Scan(&hotelID); err != nil {
+			return nil, fmt.Errorf("failed to scan hotel catalog: %w", err)
+		}
+		hotelIDs = append(hotelIDs, hotelID)
+	}
+	return hotelIDs, rows.Err()
+}
+
+// ListCatalog returns every catalog entry, highest priority first.
+func (r *HotelRepository) ListCatalog(ctx context.Context) ([]CatalogEntry, error) {
+	query := `
+		SELECT hotel_id, active, priority, created_at, updated_at
+		FROM hotel_catalog
+		ORDER BY priority DESC, hotel_id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hotel catalog: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []CatalogEntry{}
+	for rows.Next() {
+		var entry CatalogEntry
+		if err := rows.Scan(&entry.HotelID, &entry.Active, &entry.Priority, &entry.CreatedAt, &entry.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan hotel catalog: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// UpsertCatalogEntry starts tracking a hotel, or updates its active flag and priority.
+func (r *HotelRepository) UpsertCatalogEntry(ctx context.Context, entry CatalogEntry) (*CatalogEntry, error) {
+	query := `
+		INSERT INTO hotel_catalog (hotel_id, active, priority)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (hotel_id) DO UPDATE SET
+			active = EXCLUDED.active,
+			priority = EXCLUDED.priority
+		RETURNING hotel_id, active, priority, created_at, updated_at`
+
+	var stored CatalogEntry
+	err := r.db.QueryRowContext(ctx, query, entry.HotelID, entry.Active, entry.Priority).
+		Scan(&stored.HotelID, &stored.Active, &stored.Priority, &stored.CreatedAt, &stored.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert hotel catalog entry: %w", err)
+	}
+	return &stored, nil
+}
+
+// DeleteCatalogEntry stops tracking a hotel. Data already synced for it is kept.
+func (r *HotelRepository) DeleteCatalogEntry(ctx context.Context, hotelID int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM hotel_catalog WHERE hotel_id = $1", hotelID)
+	if err != nil {
+		return fmt.Errorf("failed to delete hotel catalog entry: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrCatalogEntryNotFound
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotelRepository_Catalog(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := NewHotelRepository(db)
+	ctx := context.Background()
+
+	activeID := randomID()
+	inactiveID := randomID()
+	t.Cleanup(func() {
+		_ = repo.DeleteCatalogEntry(ctx, activeID)
+		_ = repo.DeleteCatalogEntry(ctx, inactiveID)
+	})
+
+	stored, err := repo.UpsertCatalogEntry(ctx, CatalogEntry{HotelID: activeID, Active: true, Priority: 10})
+	require.NoError(t, err)
+	assert.Equal(t, activeID, stored.HotelID)
+	assert.True(t, stored.Active)
+	assert.Equal(t, 10, stored.Priority)
+
+	_, err = repo.UpsertCatalogEntry(ctx, CatalogEntry{HotelID: inactiveID, Active: true})
+	require.NoError(t, err)
+	stored, err = repo.UpsertCatalogEntry(ctx, CatalogEntry{HotelID: inactiveID, Active: false})
+	require.NoError(t, err)
+	assert.False(t, stored.Active)
+
+	active, err := repo.GetCatalogHotelIDs(ctx, true)
+	require.NoError(t, err)
+	assert.Contains(t, active, activeID)
+	assert.NotContains(t, active, inactiveID)
+
+	all, err := repo.GetCatalogHotelIDs(ctx, false)
+	require.NoError(t, err)
+	assert.Contains(t, all, activeID)
+	assert.Contains(t, all, inactiveID)
+
+	require.NoError(t, repo.DeleteCatalogEntry(ctx, activeID))
+	assert.ErrorIs(t, repo.DeleteCatalogEntry(ctx, activeID), ErrCatalogEntryNotFound)
+}
@@ -302,3 +302,163 @@ func TestConnectionStringFormat(t *testing.T) {
 		}
 	})
 }
+
+func TestConfig_DSN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  Config
+		want    string
+		wantErr string
+	}{
+		{
+			name: "fields",
+			config: Config{
+				Host:     "db",
+				Port:     5433,
+				User:     "cupid",
+				Password: `it's a \secret`,
+				DBName:   "cupid",
+				SSLMode:  "disable",
+			},
+			want: `host='db' port='5433' user='cupid' password='it\'s a \\secret' dbname='cupid' sslmode='disable'`,
+		},
+		{
+			name: "url with timeout and application name",
+			config: Config{
+				URL:              "postgres://cupid:secret@db:5433/cupid?sslmode=require",
+				Host:             "ignored",
+				StatementTimeout: 1500 * time.Millisecond,
+				ApplicationName:  "cupid-server",
+			},
+			want: `dbname='cupid' host='db' password='secret' port='5433' sslmode='require' user='cupid' application_name='cupid-server' statement_timeout='1500'`,
+		},
+		{
+			name:    "invalid url",
+			config:  Config{URL: "mysql://db/cupid"},
+			wantErr: "invalid database URL",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dsn, err := tc.config.DSN()
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, dsn)
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			want: Config{
+				Host:            "localhost",
+				Port:            5432,
+				User:            "cupid",
+				Password:        "cupid123",
+				DBName:          "cupid",
+				SSLMode:         "disable",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ApplicationName: "cupid-test",
+			},
+		},
+		{
+			name: "all settings",
+			env: map[string]string{
+				"DATABASE_URL":          "postgres://db/cupid",
+				"DB_PORT":               "6432",
+				"DB_MAX_OPEN_CONNS":     "50",
+				"DB_MAX_IDLE_CONNS":     "10",
+				"DB_CONN_MAX_LIFETIME":  "1h",
+				"DB_CONN_MAX_IDLE_TIME": "2m",
+				"DB_STATEMENT_TIMEOUT":  "30s",
+				"DB_APPLICATION_NAME":   "custom",
+			},
+			want: Config{
+				URL:              "postgres://db/cupid",
+				Host:             "localhost",
+				Port:             6432,
+				User:             "cupid",
+				Password:         "cupid123",
+				DBName:           "cupid",
+				SSLMode:          "disable",
+				MaxOpenConns:     50,
+				MaxIdleConns:     10,
+				ConnMaxLifetime:  time.Hour,
+				ConnMaxIdleTime:  2 * time.Minute,
+				StatementTimeout: 30 * time.Second,
+				ApplicationName:  "custom",
+			},
+		},
+		{
+			name:    "invalid port",
+			env:     map[string]string{"DB_PORT": "postgres"},
+			wantErr: `invalid DB_PORT "postgres"`,
+		},
+		{
+			name:    "zero max open connections",
+			env:     map[string]string{"DB_MAX_OPEN_CONNS": "0"},
+			wantErr: "invalid DB_MAX_OPEN_CONNS",
+		},
+		{
+			name:    "invalid statement timeout",
+			env:     map[string]string{"DB_STATEMENT_TIMEOUT": "30"},
+			wantErr: `invalid DB_STATEMENT_TIMEOUT "30"`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			getenv := func(key string) string { return tc.env[key] }
+			config, err := configFromEnv(getenv, "cupid-test")
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, config)
+		})
+	}
+}
+
+func TestNewConnection_Settings(t *testing.T) {
+	t.Parallel()
+
+	config, err := ConfigFromEnv("cupid-test")
+	require.NoError(t, err)
+	config.MaxOpenConns = 7
+	config.StatementTimeout = 100 * time.Millisecond
+
+	db, err := NewConnection(config)
+	require.NoError(t, err)
+	defer db.Close()
+
+	assert.Equal(t, 7, db.Stats().MaxOpenConnections)
+
+	var applicationName string
+	require.NoError(t, db.QueryRowContext(context.Background(), `SELECT current_setting('application_name')`).Scan(&applicationName))
+	assert.Equal(t, "cupid-test", applicationName)
+
+	_, err = db.ExecContext(context.Background(), `SELECT pg_sleep(1)`)
+	assert.ErrorContains(t, err, "statement timeout")
+}
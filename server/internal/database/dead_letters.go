@@ -0,0 +1,216 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Dead letter statuses.
+const (
+	DeadLetterRetrying = "retrying"
+	DeadLetterParked   = "parked"
+)
+
+// DeadLetter is a hotel whose last sync of an endpoint type failed.
+type DeadLetter struct {
+	HotelID       int        `json:"hotel_id"`
+	EndpointType  string     `json:"endpoint_type"`
+	Status        string     `json:"status"`
+	ErrorClass    string     `json:"error_class"`
+	LastError     string     `json:"last_error"`
+	HTTPStatus    int        `json:"http_status,omitempty"`
+	Attempts      int        `json:"attempts"`
+	FirstFailedAt time.Time  `json:"first_failed_at"`
+	LastFailedAt  time.Time  `json:"last_failed_at"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+}
+
+// DeadLetterPolicy decides when a failed hotel is retried and when it is parked.
+type DeadLetterPolicy struct {
+	// BaseDelay is the wait after the first failure; it doubles with every attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between attempts.
+	MaxDelay time.Duration
+	// MaxAttempts parks a hotel once it has failed this many times.
+	MaxAttempts int
+}
+
+// DefaultDeadLetterPolicy retries after 15m, 30m, 1h, ... up to 24h and parks after 5 failures.
+func DefaultDeadLetterPolicy() DeadLetterPolicy {
+	return DeadLetterPolicy{BaseDelay: 15 * time.Minute, MaxDelay: 24 * time.Hour, MaxAttempts: 5}
+}
+
+// Next returns when a hotel that has now failed attempts times is retried,
+// and whether it is parked instead.
+func (p DeadLetterPolicy) Next(attempts int, now time.Time) (time.Time, bool) {
+	if p.MaxAttempts > 0 && attempts >= p.MaxAttempts {
+		return time.Time{}, true
+	}
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return now.Add(delay), false
+}
+
+const deadLetterColumns = `hotel_id, endpoint_type, status, error_class, last_error, COALESCE(http_status, 0),
+	attempts, first_failed_at, last_failed_at, next_attempt_at`
+
+func scanDeadLetter(row interface{ Scan(...any) error }) (*DeadLetter, error) {
+	var d DeadLetter
+	var nextAttemptAt sql.NullTime
+	err := row.Scan(&d.HotelID, &d.EndpointType, &d.Status, &d.ErrorClass, &d.LastError, &d.HTTPStatus,
+		&d.Attempts, &d.FirstFailedAt, &d.LastFailedAt, &nextAttemptAt)
+	if err != nil {
+		return nil, err
+	}
+	if nextAttemptAt.Valid {
+		d.NextAttemptAt = &nextAttemptAt.Time
+	}
+	return &d, nil
+}
+
+// RecordSyncFailure adds a failed hotel to the dead-letter queue, or bumps its
+// attempt count, and schedules its next attempt according to policy.
+func (r *HotelRepository) RecordSyncFailure(ctx context.Context, failure DeadLetter, policy DeadLetterPolicy) (*DeadLetter, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				log.Printf("failed to rollback transaction: %v", rbErr)
+			}
+		}
+	}()
+
+	attempts := 0
+	err = tx.QueryRowContext(ctx,
+		`SELECT attempts FROM sync_dead_letters WHERE hotel_id = $1 AND endpoint_type = $2 FOR UPDATE`,
+		failure.HotelID, failure.EndpointType).Scan(&attempts)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to query dead letter: %w", err)
+	}
+	attempts++
+
+	status := DeadLetterRetrying
+	var nextAttemptAt *time.Time
+	next, parked := policy.Next(attempts, time.Now())
+	if parked {
+		status = DeadLetterParked
+	} else {
+		nextAttemptAt = &next
+	}
+
+	query := `
+		INSERT INTO sync_dead_letters (
+			hotel_id, endpoint_type, status, error_class, last_error, http_status, attempts, next_attempt_at
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7, $8)
+		ON CONFLICT (hotel_id, endpoint_type) DO UPDATE SET
+			status = EXCLUDED.status,
+			error_class = EXCLUDED.error_class,
+			last_error = EXCLUDED.last_error,
+			http_status = EXCLUDED.http_status,
+			attempts = EXCLUDED.attempts,
+			last_failed_at = NOW(),
+			next_attempt_at = EXCLUDED.next_attempt_at
+		RETURNING ` + deadLetterColumns
+
+	letter, err := scanDeadLetter(tx.QueryRowContext(ctx, query, failure.HotelID, failure.EndpointType, status,
+		failure.ErrorClass, failure.LastError, failure.HTTPStatus, attempts, nextAttemptAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to store dead letter: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	return letter, nil
+}
+
+// ClearDeadLetter removes a hotel from the dead-letter queue after a successful sync.
+func (r *HotelRepository) ClearDeadLetter(ctx context.Context, hotelID int, endpointType string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM sync_dead_letters WHERE hotel_id = $1 AND endpoint_type = $2`, hotelID, endpointType)
+	if err != nil {
+		return fmt.Errorf("failed to clear dead letter: %w", err)
+	}
+	return nil
+}
+
+// ListDeadLetters returns dead letters, optionally filtered by endpoint type
+// and status, parked first and then by next attempt.
+func (r *HotelRepository) ListDeadLetters(ctx context.Context, endpointType, status string) ([]DeadLetter, error) {
+	query := `SELECT ` + deadLetterColumns + ` FROM sync_dead_letters
+		WHERE ($1 = '' OR endpoint_type = $1) AND ($2 = '' OR status = $2)
+		ORDER BY status, next_attempt_at NULLS FIRST, hotel_id`
+
+	rows, err := r.db.QueryContext(ctx, query, endpointType, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := []DeadLetter{}
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dead letter: %w", err)
+		}
+		letters = append(letters, *letter)
+	}
+	return letters, rows.Err()
+}
+
+// RequeueDeadLetters makes dead letters due immediately, unparking them. Entries
+// are optionally filtered by endpoint type, status and hotel. It returns the
+// number of entries requeued.
+func (r *HotelRepository) RequeueDeadLetters(ctx context.Context, endpointType, status string, hotelIDs []int) (int64, error) {
+	query := `
+		UPDATE sync_dead_letters SET status = $4, next_attempt_at = NOW()
+		WHERE ($1 = '' OR endpoint_type = $1) AND ($2 = '' OR status = $2)
+			AND (cardinality($3::int[]) = 0 OR hotel_id = ANY($3::int[]))`
+
+	result, err := r.db.ExecContext(ctx, query, endpointType, status, pq.Array(int64s(hotelIDs)), DeadLetterRetrying)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue dead letters: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// PurgeDeadLetters deletes dead letters, optionally filtered by endpoint type,
+// status and hotel. It returns the number of entries deleted.
+func (r *HotelRepository) PurgeDeadLetters(ctx context.Context, endpointType, status string, hotelIDs []int) (int64, error) {
+	query := `
+		DELETE FROM sync_dead_letters
+		WHERE ($1 = '' OR endpoint_type = $1) AND ($2 = '' OR status = $2)
+			AND (cardinality($3::int[]) = 0 OR hotel_id = ANY($3::int[]))`
+
+	result, err := r.db.ExecContext(ctx, query, endpointType, status, pq.Array(int64s(hotelIDs)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge dead letters: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func int64s(ids []int) []int64 {
+	out := make([]int64, len(ids))
+	for i, id := range ids {
+		out[i] = int64(id)
+	}
+	return out
+}
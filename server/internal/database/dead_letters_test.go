@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterPolicy_Next(t *testing.T) {
+	t.Parallel()
+
+	policy := DeadLetterPolicy{BaseDelay: time.Minute, MaxDelay: 10 * time.Minute, MaxAttempts: 6}
+	now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		attempts   int
+		wantDelay  time.Duration
+		wantParked bool
+	}{
+		{attempts: 1, wantDelay: time.Minute},
+		{attempts: 2, wantDelay: 2 * time.Minute},
+		{attempts: 3, wantDelay: 4 * time.Minute},
+		{attempts: 4, wantDelay: 8 * time.Minute},
+		{attempts: 5, wantDelay: 10 * time.Minute},
+		{attempts: 6, wantParked: true},
+	}
+
+	for _, tc := range tests {
+		next, parked := policy.Next(tc.attempts, now)
+		assert.Equal(t, tc.wantParked, parked, "attempts=%d", tc.attempts)
+		if !tc.wantParked {
+			assert.Equal(t, now.Add(tc.wantDelay), next, "attempts=%d", tc.attempts)
+		}
+	}
+}
+
+func TestHotelRepository_DeadLetters(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := NewHotelRepository(db)
+	ctx := context.Background()
+
+	hotelID := randomID()
+	endpointType := "content"
+	policy := DeadLetterPolicy{BaseDelay: time.Minute, MaxDelay: time.Hour, MaxAttempts: 2}
+	failure := DeadLetter{HotelID: hotelID, EndpointType: endpointType, ErrorClass: "server_error", LastError: "boom", HTTPStatus: 503}
+
+	letter, err := repo.RecordSyncFailure(ctx, failure, policy)
+	require.NoError(t, err)
+	assert.Equal(t, DeadLetterRetrying, letter.Status)
+	assert.Equal(t, 1, letter.Attempts)
+	assert.Equal(t, 503, letter.HTTPStatus)
+	require.NotNil(t, letter.NextAttemptAt)
+
+	letter, err = repo.RecordSyncFailure(ctx, failure, policy)
+	require.NoError(t, err)
+	assert.Equal(t, DeadLetterParked, letter.Status)
+	assert.Equal(t, 2, letter.Attempts)
+	assert.Nil(t, letter.NextAttemptAt)
+
+	requeued, err := repo.RequeueDeadLetters(ctx, endpointType, DeadLetterParked, []int{hotelID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), requeued)
+
+	letters, err := repo.ListDeadLetters(ctx, endpointType, DeadLetterRetrying)
+	require.NoError(t, err)
+	var found bool
+	for _, l := range letters {
+		if l.HotelID == hotelID {
+			found = true
+			assert.Equal(t, 2, l.Attempts)
+		}
+	}
+	assert.True(t, found)
+
+	require.NoError(t, repo.ClearDeadLetter(ctx, hotelID, endpointType))
+	purged, err := repo.PurgeDeadLetters(ctx, endpointType, "", []int{hotelID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), purged)
+}
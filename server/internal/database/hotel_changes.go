@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+// HotelChange is the set of field-level changes detected when a new version
+// of a hotel was stored.
+type HotelChange struct {
+	ID           int64                   `json:"id"`
+	HotelID      int                     `json:"hotel_id"`
+	PreviousHash string                  `json:"previous_hash"`
+	ContentHash  string                  `json:"content_hash"`
+	Summary      string                  `json:"summary"`
+	Changes      []client.PropertyChange `json:"changes"`
+	DetectedAt   time.Time               `json:"detected_at"`
+}
+
+// snapshot is the last stored version of a hotel.
+type snapshot struct {
+	hash    string
+	content []byte
+}
+
+func (r *HotelRepository) loadSnapshot(ctx context.Context, tx *sql.Tx, hotelID int) (*snapshot, error) {
+	query := `SELECT content_hash, content FROM hotel_snapshots WHERE hotel_id = $1 FOR UPDATE`
+
+	var s snapshot
+	err := tx.QueryRowContext(ctx, query, hotelID).Scan(&s.hash, &s.content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *HotelRepository) storeSnapshot(ctx context.Context, tx *sql.Tx, hotelID int, contentHash string, content []byte) error {
+	query := `
+		INSERT INTO hotel_snapshots (hotel_id, content_hash, content, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (hotel_id) DO UPDATE SET
+			content_hash = EXCLUDED.content_hash,
+			content = EXCLUDED.content,
+			updated_at = NOW()`
+
+	_, err := tx.ExecContext(ctx, query, hotelID, contentHash, content)
+	return err
+}
+
+// storeChanges records the diff between the previous snapshot and property.
+func (r *HotelRepository) storeChanges(ctx context.Context, tx *sql.Tx, hotelID int, previous *snapshot, contentHash string, property *client.Property) error {
+	before, err := client.ParseProperty(previous.content)
+	if err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	changes, err := client.DiffProperties(before, property)
+	if err != nil {
+		return fmt.Errorf("failed to diff property: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO hotel_changes (hotel_id, previous_hash, content_hash, summary, changes)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err = tx.ExecContext(ctx, query, hotelID, previous.hash, contentHash, client.SummarizeChanges(changes), changesJSON)
+	return err
+}
+
+// GetHotelChanges returns the recorded changes of a hotel, most recent first.
+// Removed hotels have none.
+func (r *HotelRepository) GetHotelChanges(ctx context.Context, hotelID, limit, offset int) ([]HotelChange, error) {
+	query := `
+		SELECT id, hotel_id, previous_hash, content_hash, summary, changes, detected_at
+		FROM hotel_changes
+		WHERE hotel_id = $1 AND ` + notRemoved("hotel_changes.hotel_id") + `
+		ORDER BY detected_at DESC, id DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, hotelID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hotel changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []HotelChange{}
+	for rows.Next() {
+		var change HotelChange
+		var changesJSON []byte
+		if err := rows.Scan(&change.ID, &change.HotelID, &change.PreviousHash, &change.ContentHash,
+			&change.Summary, &changesJSON, &change.DetectedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan hotel change: %w", err)
+		}
+		if err := json.Unmarshal(changesJSON, &change.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode hotel change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
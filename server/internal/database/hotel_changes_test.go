@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+func TestHotelRepository_StoreProperty_RecordsChanges(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := NewHotelRepository(db)
+	ctx := context.Background()
+
+	property := createRandomProperty()
+	property.Rating = 8.3
+	require.NoError(t, repo.StoreProperty(ctx, property))
+
+	// Storing the same content again is a no-op and records no change.
+	require.NoError(t, repo.StoreProperty(ctx, property))
+	changes, err := repo.GetHotelChanges(ctx, property.HotelID, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	updated := *property
+	updated.Rating = 8.4
+	updated.Photos = []client.Photo{{URL: "https://example.com/a.jpg"}, {URL: "https://example.com/b.jpg"}}
+	require.NoError(t, repo.StoreProperty(ctx, &updated))
+
+	changes, err = repo.GetHotelChanges(ctx, property.HotelID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "rating 8.3→8.4; 2 photos added", changes[0].Summary)
+	assert.Len(t, changes[0].Changes, 3)
+	assert.NotEqual(t, changes[0].PreviousHash, changes[0].ContentHash)
+}
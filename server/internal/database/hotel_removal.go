@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/cache"
+)
+
+// ErrHotelGone is returned for hotels soft-deleted after they disappeared upstream.
+var ErrHotelGone = errors.New("hotel removed upstream")
+
+// notRemoved returns the SQL condition that the hotel hotelIDColumn refers to
+// is not soft-deleted, for the reads of data attached to hotels.
+func notRemoved(hotelIDColumn string) string {
+	return "NOT EXISTS (SELECT 1 FROM hotels h WHERE h.hotel_id = " + hotelIDColumn + " AND h.deleted_at IS NOT NULL)"
+}
+
+// invalidateRemoval evicts everything cached for a hotel whose removal
+// changed: reviews and translations are hidden along with the hotel.
+func (r *HotelRepository) invalidateRemoval(ctx context.Context, hotelID int) {
+	for _, entity := range []string{cache.EntityHotel, cache.EntityReviews, cache.EntityTranslations} {
+		r.invalidate(ctx, hotelID, entity)
+	}
+}
+
+// MarkHotelGone records that a hotel was not found upstream. The first call
+// starts its grace period; the hotel is soft-deleted by the first call once it
+// has been missing for grace. It reports whether the hotel is deleted; hotels
+// that were never stored are ignored.
+func (r *HotelRepository) MarkHotelGone(ctx context.Context, hotelID int, grace time.Duration) (bool, error) {
+	query := `
+		UPDATE hotels SET
+			gone_since = COALESCE(gone_since, NOW()),
+			deleted_at = COALESCE(deleted_at,
+				CASE WHEN COALESCE(gone_since, NOW()) <= NOW() - make_interval(secs => $2) THEN NOW() END)
+		WHERE hotel_id = $1
+		RETURNING deleted_at IS NOT NULL`
+
+	var deleted bool
+	err := r.db.QueryRowContext(ctx, query, hotelID, grace.Seconds()).Scan(&deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to mark hotel gone: %w", err)
+	}
+	if deleted {
+		r.invalidateRemoval(ctx, hotelID)
+	}
+	return deleted, nil
+}
+
+// ClearHotelGone undoes MarkHotelGone for a hotel found upstream again. It
+// reports whether the hotel had been marked.
+func (r *HotelRepository) ClearHotelGone(ctx context.Context, hotelID int) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE hotels SET gone_since = NULL, deleted_at = NULL WHERE hotel_id = $1 AND gone_since IS NOT NULL", hotelID)
+	if err != nil {
+		return false, fmt.Errorf("failed to clear gone hotel: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+	r.invalidateRemoval(ctx, hotelID)
+	return true, nil
+}
+
+// RestoreHotel serves a soft-deleted hotel again and restarts its grace
+// period, so a hotel still missing upstream is deleted again only after it.
+func (r *HotelRepository) RestoreHotel(ctx context.Context, hotelID int) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE hotels SET gone_since = NULL, deleted_at = NULL WHERE hotel_id = $1", hotelID)
+	if err != nil {
+		return fmt.Errorf("failed to restore hotel: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrHotelNotFound
+	}
+	r.invalidateRemoval(ctx, hotelID)
+	return nil
+}
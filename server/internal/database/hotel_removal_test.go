@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/cache"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+func TestHotelRepository_MarkHotelGone(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := NewHotelRepository(db)
+	ctx := context.Background()
+
+	property := createRandomProperty()
+	require.NoError(t, repo.StoreProperty(ctx, property))
+
+	// Within the grace period the hotel stays live.
+	deleted, err := repo.MarkHotelGone(ctx, property.HotelID, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	_, err = repo.GetHotelByID(ctx, property.HotelID)
+	require.NoError(t, err)
+
+	deleted, err = repo.MarkHotelGone(ctx, property.HotelID, 0)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	_, err = repo.GetHotelByID(ctx, property.HotelID)
+	assert.ErrorIs(t, err, ErrHotelGone)
+
+	hotels, err := repo.GetHotels(ctx, 10000, 0)
+	require.NoError(t, err)
+	for _, hotel := range hotels {
+		assert.NotEqual(t, property.HotelID, hotel.HotelID)
+	}
+
+	restored, err := repo.ClearHotelGone(ctx, property.HotelID)
+	require.NoError(t, err)
+	assert.True(t, restored)
+	_, err = repo.GetHotelByID(ctx, property.HotelID)
+	require.NoError(t, err)
+
+	restored, err = repo.ClearHotelGone(ctx, property.HotelID)
+	require.NoError(t, err)
+	assert.False(t, restored)
+
+	deleted, err = repo.MarkHotelGone(ctx, randomID(), 0)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestHotelRepository_RestoreHotel(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := NewHotelRepository(db)
+	ctx := context.Background()
+
+	property := createRandomProperty()
+	require.NoError(t, repo.StoreProperty(ctx, property))
+	_, err := repo.MarkHotelGone(ctx, property.HotelID, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RestoreHotel(ctx, property.HotelID))
+	_, err = repo.GetHotelByID(ctx, property.HotelID)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, repo.RestoreHotel(ctx, randomID()), ErrHotelNotFound)
+}
+
+func TestHotelRepository_RemovedHotelHidesItsData(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := NewHotelRepository(db)
+	invalidator := &recordingInvalidator{}
+	repo.SetInvalidator(invalidator)
+	ctx := context.Background()
+
+	property := createRandomProperty()
+	require.NoError(t, repo.StoreProperty(ctx, property))
+	require.NoError(t, repo.StoreReviews(ctx, property.HotelID, []client.Review{{ReviewerName: "Ann", Rating: 5}}))
+	require.NoError(t, repo.StoreTranslations(ctx, property.HotelID, []client.Translation{{LanguageCode: "fr", FieldName: "name", TranslatedText: "Hôtel"}}))
+	updated := *property
+	updated.Rating++
+	require.NoError(t, repo.StoreProperty(ctx, &updated))
+
+	invalidator.seen = nil
+	_, err := repo.MarkHotelGone(ctx, property.HotelID, 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []cache.Invalidation{
+		{HotelID: property.HotelID, Entity: cache.EntityHotel},
+		{HotelID: property.HotelID, Entity: cache.EntityReviews},
+		{HotelID: property.HotelID, Entity: cache.EntityTranslations},
+	}, invalidator.seen)
+
+	reviews, err := repo.GetHotelReviews(ctx, property.HotelID)
+	require.NoError(t, err)
+	assert.Empty(t, reviews)
+	translations, err := repo.GetHotelTranslations(ctx, property.HotelID, "fr")
+	require.NoError(t, err)
+	assert.Empty(t, translations)
+	changes, err := repo.GetHotelChanges(ctx, property.HotelID, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	require.NoError(t, repo.RestoreHotel(ctx, property.HotelID))
+	reviews, err = repo.GetHotelReviews(ctx, property.HotelID)
+	require.NoError(t, err)
+	assert.Len(t, reviews, 1)
+	translations, err = repo.GetHotelTranslations(ctx, property.HotelID, "fr")
+	require.NoError(t, err)
+	assert.Len(t, translations, 1)
+	changes, err = repo.GetHotelChanges(ctx, property.HotelID, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+}
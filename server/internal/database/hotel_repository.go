@@ -8,6 +8,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/vrnvu/cupid/internal/cache"
 	"github.com/vrnvu/cupid/internal/client"
 )
 
@@ -23,27 +24,93 @@ type Repository interface {
 	StoreTranslations(ctx context.Context, hotelID int, translations []client.Translation) error
 	GetHotels(ctx context.Context, limit, offset int) ([]client.Property, error)
 	GetHotelByID(ctx context.Context, hotelID int) (*client.Property, error)
+	GetTopHotelIDs(ctx context.Context, limit int) ([]int, error)
 	GetHotelReviews(ctx context.Context, hotelID int) ([]client.Review, error)
 	GetHotelTranslations(ctx context.Context, hotelID int, languageCode string) ([]client.Translation, error)
+	GetHotelChanges(ctx context.Context, hotelID, limit, offset int) ([]HotelChange, error)
 	SearchReviewsByVector(ctx context.Context, queryEmbedding []float64, limit int, threshold float64) ([]client.Review, error)
 	GetReviewsNeedingEmbeddings(ctx context.Context, limit int) ([]int, error)
+	GetCatalogHotelIDs(ctx context.Context, activeOnly bool) ([]int, error)
+	ListCatalog(ctx context.Context) ([]CatalogEntry, error)
+	UpsertCatalogEntry(ctx context.Context, entry CatalogEntry) (*CatalogEntry, error)
+	DeleteCatalogEntry(ctx context.Context, hotelID int) error
+	ListSyncRuns(ctx context.Context, limit, offset int) ([]SyncRun, error)
+	GetSyncRun(ctx context.Context, runID int64) (*SyncRun, error)
+	GetSyncRunItems(ctx context.Context, runID int64, status string) ([]SyncRunItem, error)
+	RestoreHotel(ctx context.Context, hotelID int) error
 	Ping(ctx context.Context) error
 }
 
 type HotelRepository struct {
 	db *DB
+	// invalidator, when set, is told about every committed write.
+	invalidator cache.Invalidator
+	// replicas, when set, serve the reads that tolerate replication lag.
+	replicas *Replicas
 }
 
 func NewHotelRepository(db *DB) *HotelRepository {
 	return &HotelRepository{db: db}
 }
 
+// SetInvalidator evicts the cached data of a hotel after every write to it.
+// Cache failures are logged and do not fail the write.
+func (r *HotelRepository) SetInvalidator(invalidator cache.Invalidator) {
+	r.invalidator = invalidator
+}
+
+// SetReplicas routes GetHotels, GetHotelByID, GetHotelReviews and
+// SearchReviewsByVector to healthy replicas. Writes, and reads that must see
+// them, stay on the primary.
+func (r *HotelRepository) SetReplicas(replicas *Replicas) {
+	r.replicas = replicas
+}
+
+// reader returns a healthy replica, or the primary when there is none. Cache
+// loads of a key invalidated more recently than the replicas are known to be
+// caught up go to the primary: a replica could still miss the write, and the
+// cache would serve what it read for a whole TTL.
+func (r *HotelRepository) reader(ctx context.Context) *DB {
+	if r.replicas == nil {
+		return r.db
+	}
+	if invalidatedAt, ok := cache.InvalidatedAt(ctx); ok && !invalidatedAt.Before(r.replicas.CaughtUpTo()) {
+		return r.db
+	}
+	if db := r.replicas.Reader(); db != nil {
+		return db
+	}
+	return r.db
+}
+
+// invalidate evicts the cached entity of a hotel after a committed write.
+func (r *HotelRepository) invalidate(ctx context.Context, hotelID int, entity string) {
+	if r.invalidator == nil {
+		return
+	}
+	if err := r.invalidator.Invalidate(ctx, cache.Invalidation{HotelID: hotelID, Entity: entity}); err != nil {
+		log.Printf("Warning: Failed to invalidate cached %s of hotel %d: %v", entity, hotelID, err)
+	}
+}
+
 // GetDB returns the underlying database connection for direct access
 func (r *HotelRepository) GetDB() *DB {
 	return r.db
 }
 
+// StoreProperty writes a property and its related rows. Properties whose
+// canonical content is unchanged since the last store are skipped; otherwise
+// the field-level diff against the previous version is recorded in hotel_changes.
 func (r *HotelRepository) StoreProperty(ctx context.Context, property *client.Property) error {
+	content, err := property.Canonical()
+	if err != nil {
+		return fmt.Errorf("failed to encode property: %w", err)
+	}
+	contentHash, err := property.ContentHash()
+	if err != nil {
+		return fmt.Errorf("failed to hash property: %w", err)
+	}
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -58,6 +125,14 @@ func (r *HotelRepository) StoreProperty(ctx context.Context, property *client.Pr
 		}
 	}()
 
+	previous, err := r.loadSnapshot(ctx, tx, property.HotelID)
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot: %w", err)
+	}
+	if previous != nil && previous.hash == contentHash {
+		return nil
+	}
+
 	hotelID, err := r.storeHotel(ctx, tx, property)
 	if err != nil {
 		return fmt.Errorf("failed to store hotel: %w", err)
@@ -87,11 +162,22 @@ func (r *HotelRepository) StoreProperty(ctx context.Context, property *client.Pr
 		return fmt.Errorf("failed to store rooms: %w", err)
 	}
 
+	if err := r.storeSnapshot(ctx, tx, hotelID, contentHash, content); err != nil {
+		return fmt.Errorf("failed to store snapshot: %w", err)
+	}
+
+	if previous != nil {
+		if err := r.storeChanges(ctx, tx, hotelID, previous, contentHash, property); err != nil {
+			return fmt.Errorf("failed to store changes: %w", err)
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	committed = true
+	r.invalidate(ctx, hotelID, cache.EntityHotel)
 	return nil
 }
 
@@ -447,10 +533,11 @@ func (r *HotelRepository) GetHotels(ctx context.Context, limit, offset int) ([]c
 		SELECT hotel_id, cupid_id, hotel_name, rating, review_count, stars, 
 		       latitude, longitude, hotel_type, chain
 		FROM hotels 
+		WHERE deleted_at IS NULL
 		ORDER BY hotel_id 
 		LIMIT $1 OFFSET $2`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query hotels: %w", err)
 	}
@@ -477,18 +564,48 @@ func (r *HotelRepository) GetHotels(ctx context.Context, limit, offset int) ([]c
 	return hotels, nil
 }
 
+// GetTopHotelIDs returns the IDs of the live hotels with the most reviews,
+// most reviewed first.
+func (r *HotelRepository) GetTopHotelIDs(ctx context.Context, limit int) ([]int, error) {
+	query := `
+		SELECT hotel_id FROM hotels
+		WHERE deleted_at IS NULL
+		ORDER BY review_count DESC NULLS LAST, hotel_id
+		LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top hotels: %w", err)
+	}
+	defer rows.Close()
+
+	var hotelIDs []int
+	for rows.Next() {
+		var hotelID int
+		if err := rows.Scan(&hotelID); err != nil {
+			return nil, fmt.Errorf("failed to scan hotel: %w", err)
+		}
+		hotelIDs = append(hotelIDs, hotelID)
+	}
+	return hotelIDs, rows.Err()
+}
+
 func (r *HotelRepository) GetHotelByID(ctx context.Context, hotelID int) (*client.Property, error) {
-	query := `SELECT hotel_id, cupid_id, hotel_name, rating, review_count FROM hotels WHERE hotel_id = $1`
+	query := `SELECT hotel_id, cupid_id, hotel_name, rating, review_count, deleted_at IS NOT NULL FROM hotels WHERE hotel_id = $1`
 
 	var property client.Property
-	err := r.db.QueryRowContext(ctx, query, hotelID).Scan(
-		&property.HotelID, &property.CupidID, &property.HotelName, &property.Rating, &property.ReviewCount)
+	var deleted bool
+	err := r.reader(ctx).QueryRowContext(ctx, query, hotelID).Scan(
+		&property.HotelID, &property.CupidID, &property.HotelName, &property.Rating, &property.ReviewCount, &deleted)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrHotelNotFound
 		}
 		return nil, err
 	}
+	if deleted {
+		return nil, ErrHotelGone
+	}
 
 	return &property, nil
 }
@@ -502,10 +619,10 @@ func (r *HotelRepository) GetHotelReviews(ctx context.Context, hotelID int) ([]c
 		SELECT id, hotel_id, reviewer_name, rating, title, content, language_code, 
 		       review_date, helpful_votes, created_at
 		FROM reviews 
-		WHERE hotel_id = $1 
+		WHERE hotel_id = $1 AND ` + notRemoved("reviews.hotel_id") + `
 		ORDER BY review_date DESC, created_at DESC`
 
-	rows, err := r.db.QueryContext(ctx, query, hotelID)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, hotelID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query reviews: %w", err)
 	}
@@ -538,6 +655,7 @@ func (r *HotelRepository) GetHotelTranslations(ctx context.Context, hotelID int,
 		       translated_text, created_at, updated_at
 		FROM translations 
 		WHERE entity_type = 'hotel' AND entity_id = $1 AND language_code = $2
+		AND ` + notRemoved("translations.entity_id") + `
 		ORDER BY field_name`
 
 	rows, err := r.db.QueryContext(ctx, query, hotelID, languageCode)
@@ -621,6 +739,7 @@ func (r *HotelRepository) StoreReviews(ctx context.Context, hotelID int, reviews
 	}
 
 	committed = true
+	r.invalidate(ctx, hotelID, cache.EntityReviews)
 	return nil
 }
 
@@ -671,6 +790,7 @@ func (r *HotelRepository) StoreTranslations(ctx context.Context, hotelID int, tr
 	}
 
 	committed = true
+	r.invalidate(ctx, hotelID, cache.EntityTranslations)
 	return nil
 }
 
@@ -691,10 +811,11 @@ func (r *HotelRepository) SearchReviewsByVector(ctx context.Context, queryEmbedd
 		WHERE embedding IS NOT NULL 
 		AND embedding_status = 'completed'
 		AND 1 - (embedding <=> $1::vector) >= $2
+		AND ` + notRemoved("reviews.hotel_id") + `
 		ORDER BY embedding <=> $1::vector
 		LIMIT $3`
 
-	rows, err := r.db.QueryContext(ctx, query, vectorStr, threshold, limit)
+	rows, err := r.reader(ctx).QueryContext(ctx, query, vectorStr, threshold, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query vector search: %w", err)
 	}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
+	"math"
 	"math/big"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -44,14 +46,8 @@ func createRandomProperty() *client.Property {
 
 // setupTestDB creates a database connection for testing
 func setupTestDB(t *testing.T) *DB {
-	config := Config{
-		Host:     "localhost",
-		Port:     5432,
-		User:     "cupid",
-		Password: "cupid123",
-		DBName:   "cupid",
-		SSLMode:  "disable",
-	}
+	config, err := ConfigFromEnv("cupid-test")
+	require.NoError(t, err)
 
 	db, err := NewConnection(config)
 	require.NoError(t, err)
@@ -170,6 +166,33 @@ func TestHotelRepository_GetHotelByID(t *testing.T) {
 	}
 }
 
+func TestHotelRepository_GetTopHotelIDs(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+	repo := NewHotelRepository(db)
+	ctx := context.Background()
+
+	// Counts above any other test data, so both hotels make the top.
+	top, second := createRandomProperty(), createRandomProperty()
+	top.ReviewCount = math.MaxInt32 - randomID()%1000
+	second.ReviewCount = top.ReviewCount - 1
+	require.NoError(t, repo.StoreProperty(ctx, second))
+	require.NoError(t, repo.StoreProperty(ctx, top))
+
+	hotelIDs, err := repo.GetTopHotelIDs(ctx, 1000)
+	require.NoError(t, err)
+	topIndex, secondIndex := slices.Index(hotelIDs, top.HotelID), slices.Index(hotelIDs, second.HotelID)
+	require.NotEqual(t, -1, topIndex)
+	require.NotEqual(t, -1, secondIndex)
+	assert.Less(t, topIndex, secondIndex)
+
+	hotelIDs, err = repo.GetTopHotelIDs(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, hotelIDs, 1)
+}
+
 func TestHotelRepository_GetHotels(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/cache"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+type recordingInvalidator struct {
+	mu   sync.Mutex
+	seen []cache.Invalidation
+}
+
+func (r *recordingInvalidator) Invalidate(_ context.Context, inv cache.Invalidation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seen = append(r.seen, inv)
+	return nil
+}
+
+func TestHotelRepository_InvalidatesAfterWrites(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := NewHotelRepository(db)
+	invalidator := &recordingInvalidator{}
+	repo.SetInvalidator(invalidator)
+	ctx := context.Background()
+
+	property := createRandomProperty()
+	require.NoError(t, repo.StoreProperty(ctx, property))
+	// Unchanged content is not rewritten and leaves the cache alone.
+	require.NoError(t, repo.StoreProperty(ctx, property))
+	require.NoError(t, repo.StoreReviews(ctx, property.HotelID, []client.Review{{ReviewerName: "Ann", Rating: 5}}))
+	require.NoError(t, repo.StoreTranslations(ctx, property.HotelID, []client.Translation{{LanguageCode: "fr", FieldName: "name", TranslatedText: "Hôtel"}}))
+
+	assert.Equal(t, []cache.Invalidation{
+		{HotelID: property.HotelID, Entity: cache.EntityHotel},
+		{HotelID: property.HotelID, Entity: cache.EntityReviews},
+		{HotelID: property.HotelID, Entity: cache.EntityTranslations},
+	}, invalidator.seen)
+}
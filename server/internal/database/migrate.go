@@ -0,0 +1,327 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the Postgres advisory lock held while migrating, so
+// concurrent migrators apply every migration once.
+const migrationLockKey int64 = 0x6d696772617465 // "migrate"
+
+// ErrSchemaOutdated is returned by Migrator.Check when migrations are pending.
+var ErrSchemaOutdated = errors.New("database schema is outdated")
+
+// Migration is a numbered schema change, read from NNN_name.up.sql and the
+// optional NNN_name.down.sql.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%03d_%s", m.Version, m.Name)
+}
+
+// MigrationStatus is a migration and the time it was applied; AppliedAt is
+// nil while it is pending.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// loadMigrations reads the migrations in fsys, oldest first.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %s has no up file", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+	return migrations, nil
+}
+
+// Migrator applies the embedded migrations and records them in the
+// schema_migrations table.
+type Migrator struct {
+	db         *DB
+	migrations []Migration
+	table      string
+}
+
+// NewMigrator returns a migrator of the migrations embedded in the binary.
+func NewMigrator(db *DB) (*Migrator, error) {
+	fsys, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, table: "schema_migrations"}, nil
+}
+
+// Latest returns the version of the newest migration, 0 if there are none.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies the pending migrations, oldest first, each in its own
+// transaction. It returns the migrations applied, up to the one that failed.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	lock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock(ctx, lock)
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		record := fmt.Sprintf(`INSERT INTO %s (version, name) VALUES ($1, $2)`, m.table)
+		if err := m.run(ctx, migration, migration.Up, record); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down reverts the last steps applied migrations, newest first. It returns
+// the migrations reverted, up to the one that failed.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	lock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock(ctx, lock)
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == "" {
+			return done, fmt.Errorf("migration %s has no down file", migration)
+		}
+		record := fmt.Sprintf(`DELETE FROM %s WHERE version = $1 AND name = $2`, m.table)
+		if err := m.run(ctx, migration, migration.Down, record); err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Baseline records the migrations up to version as applied without running
+// them, for databases whose schema was created before migrations were
+// tracked. It returns the migrations recorded.
+func (m *Migrator) Baseline(ctx context.Context, version int) ([]Migration, error) {
+	lock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock(ctx, lock)
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			break
+		}
+		query := fmt.Sprintf(`INSERT INTO %s (version, name) VALUES ($1, $2) ON CONFLICT (version) DO NOTHING`, m.table)
+		result, err := m.db.ExecContext(ctx, query, migration.Version, migration.Name)
+		if err != nil {
+			return done, fmt.Errorf("failed to record migration %s: %w", migration, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			done = append(done, migration)
+		}
+	}
+	return done, nil
+}
+
+// Status returns every known migration, oldest first, with the time it was
+// applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i].Migration = migration
+		if appliedAt, ok := applied[migration.Version]; ok {
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Check returns ErrSchemaOutdated when any known migration is pending.
+// Versions applied by newer binaries are fine.
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	var pending []string
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, status.String())
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending migrations, first %s", ErrSchemaOutdated, len(pending), pending[0])
+	}
+	return nil
+}
+
+// lock takes the migration lock and creates the migrations table.
+func (m *Migrator) lock(ctx context.Context) (*AdvisoryLock, error) {
+	lock, err := m.db.AdvisoryLock(ctx, migrationLockKey)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`, m.table)
+	if _, err := m.db.ExecContext(ctx, query); err != nil {
+		unlock(ctx, lock)
+		return nil, fmt.Errorf("failed to create %s: %w", m.table, err)
+	}
+	return lock, nil
+}
+
+// unlock releases the migration lock, even when ctx is done.
+func unlock(ctx context.Context, lock *AdvisoryLock) {
+	if err := lock.Release(context.WithoutCancel(ctx)); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// applied returns the applied versions and when they were applied. A missing
+// migrations table means none were.
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, m.table).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check %s: %w", m.table, err)
+	}
+	applied := make(map[int]time.Time)
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := m.db.QueryContext(ctx, fmt.Sprintf(`SELECT version, applied_at FROM %s`, m.table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", m.table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", m.table, err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// run executes the SQL of a migration and the statement recording it in one
+// transaction.
+func (m *Migrator) run(ctx context.Context, migration Migration, script, record string) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				log.Printf("failed to rollback transaction: %v", rbErr)
+			}
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("failed to run migration %s: %w", migration, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, migration.Version, migration.Name); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", migration, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", migration, err)
+	}
+	committed = true
+	return nil
+}
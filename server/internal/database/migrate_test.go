@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	t.Parallel()
+
+	migrations, err := loadMigrations(fstest.MapFS{
+		"010_add_b.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"002_add_a.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+		"002_add_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	})
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, Migration{Version: 2, Name: "add_a", Up: "CREATE TABLE a ();", Down: "DROP TABLE a;"}, migrations[0])
+	assert.Equal(t, "010_add_b", migrations[1].String())
+	assert.Empty(t, migrations[1].Down)
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "invalid name",
+			files:   fstest.MapFS{"add_a.sql": {}},
+			wantErr: "invalid migration file name: add_a.sql",
+		},
+		{
+			name:    "zero version",
+			files:   fstest.MapFS{"000_add_a.up.sql": {}},
+			wantErr: "invalid migration version",
+		},
+		{
+			name:    "down only",
+			files:   fstest.MapFS{"001_add_a.down.sql": {Data: []byte("DROP TABLE a;")}},
+			wantErr: "migration 001_add_a has no up file",
+		},
+		{
+			name: "two names",
+			files: fstest.MapFS{
+				"001_add_a.up.sql": {Data: []byte("CREATE TABLE a ();")},
+				"001_add_b.up.sql": {Data: []byte("CREATE TABLE b ();")},
+			},
+			wantErr: "migration 1 has two names",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := loadMigrations(tc.files)
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	t.Parallel()
+
+	migrator, err := NewMigrator(nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrator.migrations)
+	for i, migration := range migrator.migrations {
+		assert.Equal(t, i+1, migration.Version, "versions are sequential")
+		assert.NotEmpty(t, migration.Down, "%s has a down file", migration)
+	}
+	assert.Equal(t, len(migrator.migrations), migrator.Latest())
+}
+
+func TestMigrator(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	ctx := context.Background()
+	suffix := randomID()
+
+	// Migrations of tables of this test only, tracked in a table of its own.
+	table := func(name string) string { return fmt.Sprintf("migrate_test_%s_%d", name, suffix) }
+	migrations, err := loadMigrations(fstest.MapFS{
+		"001_add_a.up.sql":   {Data: []byte("CREATE TABLE " + table("a") + " (id INTEGER);")},
+		"001_add_a.down.sql": {Data: []byte("DROP TABLE " + table("a") + ";")},
+		"002_add_b.up.sql":   {Data: []byte("CREATE TABLE " + table("b") + " (id INTEGER REFERENCES missing_table);")},
+		"002_add_b.down.sql": {Data: []byte("DROP TABLE " + table("b") + ";")},
+	})
+	require.NoError(t, err)
+	migrator := &Migrator{db: db, migrations: migrations, table: table("versions")}
+	t.Cleanup(func() {
+		for _, name := range []string{"a", "b", "versions"} {
+			_, _ = db.ExecContext(context.Background(), "DROP TABLE IF EXISTS "+table(name))
+		}
+	})
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Nil(t, statuses[0].AppliedAt)
+	assert.ErrorIs(t, migrator.Check(ctx), ErrSchemaOutdated)
+
+	// The second migration fails and is rolled back; the first stays applied.
+	applied, err := migrator.Up(ctx)
+	assert.ErrorContains(t, err, "failed to run migration 002_add_b")
+	require.Len(t, applied, 1)
+	statuses, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt)
+
+	migrator.migrations[1].Up = "CREATE TABLE " + table("b") + " (id INTEGER);"
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, 1)
+	require.NoError(t, migrator.Check(ctx))
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied, "nothing is pending")
+
+	reverted, err := migrator.Down(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, 2, reverted[0].Version)
+	var exists bool
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, table("b")).Scan(&exists))
+	assert.False(t, exists)
+
+	// Baseline records without running.
+	recorded, err := migrator.Baseline(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, 2, recorded[0].Version)
+	require.NoError(t, migrator.Check(ctx))
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, table("b")).Scan(&exists))
+	assert.False(t, exists)
+}
@@ -0,0 +1,19 @@
+-- Reverts the initial schema
+
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS translations;
+DROP TABLE IF EXISTS room_photos;
+DROP TABLE IF EXISTS room_amenities;
+DROP TABLE IF EXISTS room_bed_types;
+DROP TABLE IF EXISTS hotel_rooms;
+DROP TABLE IF EXISTS hotel_policies;
+DROP TABLE IF EXISTS hotel_facilities;
+DROP TABLE IF EXISTS hotel_photos;
+DROP TABLE IF EXISTS hotel_checkin_instructions;
+DROP TABLE IF EXISTS hotel_checkins;
+DROP TABLE IF EXISTS hotel_addresses;
+DROP TABLE IF EXISTS hotels;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
+
+DROP EXTENSION IF EXISTS "uuid-ossp";
@@ -0,0 +1,13 @@
+-- Reverts vector search on reviews
+
+DROP TRIGGER IF EXISTS update_reviews_embedding_status ON reviews;
+DROP FUNCTION IF EXISTS update_embedding_status();
+
+DROP INDEX IF EXISTS idx_reviews_embedding_status;
+DROP INDEX IF EXISTS idx_reviews_embedding_hnsw;
+
+ALTER TABLE reviews DROP COLUMN IF EXISTS embedding_updated_at;
+ALTER TABLE reviews DROP COLUMN IF EXISTS embedding_status;
+ALTER TABLE reviews DROP COLUMN IF EXISTS embedding;
+
+DROP EXTENSION IF EXISTS vector;
@@ -0,0 +1,12 @@
+-- HTTP cache validators for conditional requests to the Cupid API
+-- data-sync stores the ETag / Last-Modified of every fetched URL so the next
+-- run can send If-None-Match / If-Modified-Since and skip unchanged payloads
+
+CREATE TABLE IF NOT EXISTS http_validators (
+    url TEXT PRIMARY KEY,
+    etag TEXT,
+    last_modified TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMENT ON TABLE http_validators IS 'ETag and Last-Modified values per upstream URL, used for conditional GETs';
@@ -0,0 +1,123 @@
+-- Catalog of hotels tracked by data-sync and embedding-generator
+-- Replaces the hotel ID lists that used to be hardcoded in the commands.
+-- Inactive hotels stay in the catalog but are skipped by syncs; higher
+-- priority hotels are synced first.
+
+CREATE TABLE IF NOT EXISTS hotel_catalog (
+    hotel_id INTEGER PRIMARY KEY,
+    active BOOLEAN NOT NULL DEFAULT true,
+    priority INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_hotel_catalog_active_priority ON hotel_catalog(active, priority DESC, hotel_id);
+
+CREATE TRIGGER update_hotel_catalog_updated_at BEFORE UPDATE ON hotel_catalog
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE hotel_catalog IS 'Hotels tracked by the sync jobs, with an active flag and a sync priority';
+
+-- Seed with the hotels previously hardcoded in data-sync
+INSERT INTO hotel_catalog (hotel_id) VALUES
+    (1641879),
+    (317597),
+    (1202743),
+    (1037179),
+    (1154868),
+    (1270324),
+    (1305326),
+    (1617655),
+    (1975211),
+    (2017823),
+    (1503950),
+    (1033299),
+    (378772),
+    (1563003),
+    (1085875),
+    (828917),
+    (830417),
+    (838887),
+    (1702062),
+    (1144294),
+    (1738870),
+    (898052),
+    (906450),
+    (906467),
+    (2241195),
+    (1244595),
+    (1277032),
+    (956026),
+    (957111),
+    (152896),
+    (896868),
+    (982911),
+    (986491),
+    (986622),
+    (988544),
+    (989315),
+    (989544),
+    (990223),
+    (990341),
+    (990370),
+    (990490),
+    (990609),
+    (990629),
+    (1259611),
+    (991819),
+    (992027),
+    (992851),
+    (993851),
+    (994085),
+    (994333),
+    (994495),
+    (994903),
+    (995227),
+    (995787),
+    (996977),
+    (1186578),
+    (999444),
+    (1000017),
+    (1000051),
+    (1198750),
+    (1001100),
+    (1001296),
+    (1001402),
+    (1002200),
+    (1003142),
+    (1004288),
+    (1006404),
+    (1006602),
+    (1006810),
+    (1006887),
+    (1007101),
+    (1007269),
+    (1007466),
+    (1011203),
+    (1011644),
+    (1011945),
+    (1012047),
+    (1012140),
+    (1012944),
+    (1023527),
+    (1013529),
+    (1013584),
+    (1014383),
+    (1015094),
+    (1016591),
+    (1016611),
+    (1017019),
+    (1017039),
+    (1017044),
+    (1018030),
+    (1018130),
+    (1018251),
+    (1018402),
+    (1018946),
+    (1019473),
+    (1020332),
+    (1020335),
+    (1020386),
+    (1021856),
+    (1022380)
+ON CONFLICT (hotel_id) DO NOTHING;
@@ -0,0 +1,35 @@
+-- History of data-sync batch runs and the outcome of every hotel in them
+-- Items are created as 'pending' when the run starts, so an interrupted run
+-- can be resumed with the hotels it never reached.
+
+CREATE TABLE IF NOT EXISTS sync_runs (
+    id BIGSERIAL PRIMARY KEY,
+    endpoint_type VARCHAR(20) NOT NULL,
+    status VARCHAR(30) NOT NULL DEFAULT 'running', -- running, completed, completed_with_errors, interrupted
+    total INTEGER NOT NULL DEFAULT 0,
+    successful INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    error_text TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS sync_run_items (
+    run_id BIGINT NOT NULL REFERENCES sync_runs(id) ON DELETE CASCADE,
+    hotel_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, succeeded, failed, skipped
+    error_text TEXT,
+    http_status INTEGER,
+    duration_ms INTEGER,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (run_id, hotel_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_runs_started_at ON sync_runs(started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sync_run_items_status ON sync_run_items(run_id, status);
+
+COMMENT ON TABLE sync_runs IS 'One row per data-sync batch run';
+COMMENT ON TABLE sync_run_items IS 'Per-hotel outcome of a data-sync batch run';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS hotel_changes;
+DROP TABLE IF EXISTS hotel_snapshots;
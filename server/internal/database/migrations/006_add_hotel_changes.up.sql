@@ -0,0 +1,26 @@
+-- Change detection for property syncs
+-- hotel_snapshots keeps the canonical content of the last stored version of
+-- each hotel so unchanged properties are not rewritten, and hotel_changes
+-- keeps the field-level diff of every version that did change.
+
+CREATE TABLE IF NOT EXISTS hotel_snapshots (
+    hotel_id INTEGER PRIMARY KEY REFERENCES hotels(hotel_id) ON DELETE CASCADE,
+    content_hash CHAR(64) NOT NULL,
+    content JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS hotel_changes (
+    id BIGSERIAL PRIMARY KEY,
+    hotel_id INTEGER NOT NULL REFERENCES hotels(hotel_id) ON DELETE CASCADE,
+    previous_hash CHAR(64) NOT NULL,
+    content_hash CHAR(64) NOT NULL,
+    summary TEXT NOT NULL,
+    changes JSONB NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_hotel_changes_hotel_id ON hotel_changes(hotel_id, detected_at DESC);
+
+COMMENT ON TABLE hotel_snapshots IS 'Canonical content and hash of the last stored version of each hotel';
+COMMENT ON TABLE hotel_changes IS 'Field-level diffs between consecutive stored versions of a hotel';
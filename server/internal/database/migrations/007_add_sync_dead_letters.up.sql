@@ -0,0 +1,22 @@
+-- Dead-letter queue for hotels that failed to sync
+-- Failed hotels are retried by later runs with exponential backoff
+-- (next_attempt_at) and parked once they reach the attempt limit.
+-- A successful sync removes the entry.
+
+CREATE TABLE IF NOT EXISTS sync_dead_letters (
+    hotel_id INTEGER NOT NULL,
+    endpoint_type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'retrying', -- retrying, parked
+    error_class VARCHAR(30) NOT NULL,
+    last_error TEXT NOT NULL,
+    http_status INTEGER,
+    attempts INTEGER NOT NULL DEFAULT 1,
+    first_failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (hotel_id, endpoint_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_dead_letters_due ON sync_dead_letters(endpoint_type, status, next_attempt_at);
+
+COMMENT ON TABLE sync_dead_letters IS 'Hotels whose last sync failed, with retry backoff state';
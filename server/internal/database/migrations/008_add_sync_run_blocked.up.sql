@@ -0,0 +1,7 @@
+-- Hotels whose step of a multi-endpoint sync was not attempted because an
+-- earlier step it depends on (content) failed for that hotel.
+-- sync_run_items.status gains the value 'blocked'.
+
+ALTER TABLE sync_runs ADD COLUMN IF NOT EXISTS blocked INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN sync_runs.blocked IS 'Hotels not synced because their content sync failed in the same pipeline';
@@ -0,0 +1,20 @@
+-- Archive of raw Cupid API responses
+-- data-sync -archive=postgres keeps every response body, gzipped, with the
+-- metadata needed to audit it and to replay parsing and storage from it.
+
+CREATE TABLE IF NOT EXISTS raw_payloads (
+    id BIGSERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    method VARCHAR(10) NOT NULL DEFAULT 'GET',
+    status_code INTEGER NOT NULL,
+    request_id TEXT,
+    fetched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    encoding VARCHAR(10) NOT NULL DEFAULT 'gzip', -- gzip
+    body BYTEA NOT NULL,
+    body_size INTEGER NOT NULL -- uncompressed size in bytes
+);
+
+CREATE INDEX IF NOT EXISTS idx_raw_payloads_fetched_at ON raw_payloads(fetched_at);
+CREATE INDEX IF NOT EXISTS idx_raw_payloads_url_fetched_at ON raw_payloads(url, fetched_at DESC);
+
+COMMENT ON TABLE raw_payloads IS 'Gzipped raw Cupid API response bodies, for auditing and replay';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_hotels_live;
+
+ALTER TABLE hotels DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE hotels DROP COLUMN IF EXISTS gone_since;
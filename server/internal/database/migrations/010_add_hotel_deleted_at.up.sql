@@ -0,0 +1,12 @@
+-- Soft deletion of hotels removed upstream
+-- gone_since is set by data-sync on the first 404 from /v3.0/property/{id};
+-- once the hotel has been missing for the grace period deleted_at is set and
+-- the API stops serving it. A successful fetch clears both.
+
+ALTER TABLE hotels ADD COLUMN IF NOT EXISTS gone_since TIMESTAMP WITH TIME ZONE;
+ALTER TABLE hotels ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_hotels_live ON hotels(hotel_id) WHERE deleted_at IS NULL;
+
+COMMENT ON COLUMN hotels.gone_since IS 'First time the hotel returned 404 upstream since it was last seen';
+COMMENT ON COLUMN hotels.deleted_at IS 'Soft deletion time; deleted hotels are not served by the API';
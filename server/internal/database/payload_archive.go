@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/compress"
+)
+
+// PayloadArchive keeps raw Cupid API responses in the raw_payloads table,
+// bodies gzipped. It implements client.PayloadArchive.
+type PayloadArchive struct {
+	db *DB
+}
+
+func NewPayloadArchive(db *DB) *PayloadArchive {
+	return &PayloadArchive{db: db}
+}
+
+func (a *PayloadArchive) ArchivePayload(ctx context.Context, p client.RawPayload) error {
+	body, err := compress.Gzip(p.Body)
+	if err != nil {
+		return fmt.Errorf("failed to compress payload: %w", err)
+	}
+
+	query := `
+		INSERT INTO raw_payloads (url, method, status_code, request_id, fetched_at, encoding, body, body_size)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, 'gzip', $6, $7)`
+
+	if _, err := a.db.ExecContext(ctx, query, p.URL, p.Method, p.StatusCode, p.RequestID, p.FetchedAt, body, len(p.Body)); err != nil {
+		return fmt.Errorf("failed to archive payload: %w", err)
+	}
+	return nil
+}
+
+func (a *PayloadArchive) WalkPayloads(ctx context.Context, filter client.PayloadFilter, fn func(client.RawPayload) error) error {
+	var since, until *time.Time
+	if !filter.Since.IsZero() {
+		since = &filter.Since
+	}
+	if !filter.Until.IsZero() {
+		until = &filter.Until
+	}
+
+	matching := `
+		SELECT id, url, method, status_code, COALESCE(request_id, '') AS request_id, fetched_at, body
+		FROM raw_payloads
+		WHERE ($1::timestamptz IS NULL OR fetched_at >= $1)
+			AND ($2::timestamptz IS NULL OR fetched_at < $2)
+			AND (NOT $3 OR status_code BETWEEN 200 AND 299)`
+	query := matching + ` ORDER BY fetched_at, id`
+	if filter.LatestOnly {
+		query = `SELECT * FROM (SELECT DISTINCT ON (url) * FROM (` + matching + `) m ORDER BY url, fetched_at DESC, id DESC) latest
+			ORDER BY fetched_at, id`
+	}
+
+	rows, err := a.db.QueryContext(ctx, query, since, until, filter.SuccessfulOnly)
+	if err != nil {
+		return fmt.Errorf("failed to query raw payloads: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var p client.RawPayload
+		var body []byte
+		if err := rows.Scan(&id, &p.URL, &p.Method, &p.StatusCode, &p.RequestID, &p.FetchedAt, &body); err != nil {
+			return fmt.Errorf("failed to scan raw payload: %w", err)
+		}
+		if p.Body, err = compress.Gunzip(body); err != nil {
+			return fmt.Errorf("failed to decompress payload of %s: %w", p.URL, err)
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+func TestPayloadArchive(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	archive := NewPayloadArchive(db)
+	ctx := context.Background()
+
+	// A window of its own keeps concurrent tests out of the results.
+	base := time.Unix(int64(randomID())*3600, 0).UTC()
+	url := fmt.Sprintf("https://content-api.cupid.travel/v3.0/property/%d", randomID())
+	for i, p := range []client.RawPayload{
+		{StatusCode: 200, RequestID: "r1", Body: []byte(`{"v":1}`)},
+		{StatusCode: 200, RequestID: "r2", Body: []byte(`{"v":2}`)},
+		{StatusCode: 503, Body: []byte(`down`)},
+	} {
+		p.URL = url
+		p.Method = "GET"
+		p.FetchedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, archive.ArchivePayload(ctx, p))
+	}
+
+	walk := func(filter client.PayloadFilter) []client.RawPayload {
+		filter.Since, filter.Until = base, base.Add(time.Hour)
+		var payloads []client.RawPayload
+		require.NoError(t, archive.WalkPayloads(ctx, filter, func(p client.RawPayload) error {
+			payloads = append(payloads, p)
+			return nil
+		}))
+		return payloads
+	}
+
+	all := walk(client.PayloadFilter{})
+	require.Len(t, all, 3)
+	assert.Equal(t, "r1", all[0].RequestID)
+	assert.Equal(t, `{"v":1}`, string(all[0].Body))
+	assert.Equal(t, 503, all[2].StatusCode)
+	assert.Empty(t, all[2].RequestID)
+
+	latest := walk(client.PayloadFilter{SuccessfulOnly: true, LatestOnly: true})
+	require.Len(t, latest, 1)
+	assert.Equal(t, "r2", latest[0].RequestID)
+	assert.Equal(t, url, latest[0].URL)
+}
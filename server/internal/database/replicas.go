@@ -0,0 +1,239 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Replica settings used when the environment leaves them unset.
+const (
+	defaultReplicaMaxLag        = 5 * time.Second
+	defaultReplicaCheckInterval = 5 * time.Second
+)
+
+// ReplicaConfig configures the read replicas. Without URLs, every query goes
+// to the primary.
+type ReplicaConfig struct {
+	URLs []string
+	// MaxLag is the replication lag above which a replica stops serving
+	// reads.
+	MaxLag time.Duration
+	// CheckInterval is how often the health and lag of replicas is checked.
+	CheckInterval time.Duration
+}
+
+// ReplicaConfigFromEnv returns the configuration read from DB_REPLICA_URLS, a
+// comma separated list of postgres:// URLs, DB_REPLICA_MAX_LAG and
+// DB_REPLICA_CHECK_INTERVAL.
+func ReplicaConfigFromEnv() (ReplicaConfig, error) {
+	return replicaConfigFromEnv(os.Getenv)
+}
+
+func replicaConfigFromEnv(getenv func(string) string) (ReplicaConfig, error) {
+	config := ReplicaConfig{MaxLag: defaultReplicaMaxLag, CheckInterval: defaultReplicaCheckInterval}
+	for _, url := range strings.Split(getenv("DB_REPLICA_URLS"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			config.URLs = append(config.URLs, url)
+		}
+	}
+
+	durations := []struct {
+		key   string
+		value *time.Duration
+	}{
+		{"DB_REPLICA_MAX_LAG", &config.MaxLag},
+		{"DB_REPLICA_CHECK_INTERVAL", &config.CheckInterval},
+	}
+	for _, setting := range durations {
+		raw := getenv(setting.key)
+		if raw == "" {
+			continue
+		}
+		value, err := time.ParseDuration(raw)
+		if err != nil || value <= 0 {
+			return ReplicaConfig{}, fmt.Errorf("invalid %s %q: must be a positive duration", setting.key, raw)
+		}
+		*setting.value = value
+	}
+	return config, nil
+}
+
+// Replicas routes reads to the replica pools that are healthy and caught up,
+// round-robin. Replicas start unhealthy until the first Check.
+type Replicas struct {
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+	// checkedAt is the UnixNano time the last Check started.
+	checkedAt atomic.Int64
+}
+
+type replica struct {
+	db      *DB
+	name    string
+	healthy atomic.Bool
+}
+
+// NewReplicas returns the replica set of dbs, which serve reads while their
+// replication lag is at most maxLag.
+func NewReplicas(dbs []*DB, maxLag time.Duration) *Replicas {
+	replicas := &Replicas{maxLag: maxLag}
+	for i, db := range dbs {
+		replicas.replicas = append(replicas.replicas, &replica{db: db, name: fmt.Sprintf("replica %d", i+1)})
+	}
+	return replicas
+}
+
+// OpenReplicas opens a pool per replica URL with the pool and session
+// settings of primary. Replicas are not connected to until checked, so one
+// that is down does not stop the caller from starting.
+func OpenReplicas(primary Config, config ReplicaConfig) (*Replicas, error) {
+	dbs := make([]*DB, 0, len(config.URLs))
+	for _, url := range config.URLs {
+		replicaConfig := primary
+		replicaConfig.URL = url
+		db, err := open(replicaConfig)
+		if err != nil {
+			for _, db := range dbs {
+				db.Close()
+			}
+			return nil, fmt.Errorf("failed to open replica: %w", err)
+		}
+		dbs = append(dbs, db)
+	}
+	return NewReplicas(dbs, config.MaxLag), nil
+}
+
+// Reader returns a healthy replica, or nil when there is none and reads
+// should go to the primary.
+func (r *Replicas) Reader() *DB {
+	healthy := 0
+	for _, replica := range r.replicas {
+		if replica.healthy.Load() {
+			healthy++
+		}
+	}
+	if healthy == 0 {
+		return nil
+	}
+	n := int(r.next.Add(1) % uint64(healthy))
+	for _, replica := range r.replicas {
+		if !replica.healthy.Load() {
+			continue
+		}
+		if n == 0 {
+			return replica.db
+		}
+		n--
+	}
+	// A replica turned unhealthy while picking.
+	return nil
+}
+
+// Check updates the health of every replica: a replica is healthy while it
+// answers and replays the primary's changes within maxLag. It returns the
+// problems found.
+func (r *Replicas) Check(ctx context.Context) error {
+	defer r.checkedAt.Store(time.Now().UnixNano())
+	var errs []error
+	for _, replica := range r.replicas {
+		err := replica.check(ctx, r.maxLag)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", replica.name, err))
+		}
+		wasHealthy := replica.healthy.Swap(err == nil)
+		switch {
+		case wasHealthy && err != nil:
+			log.Printf("Warning: Database %s is unhealthy, reading from the primary: %v", replica.name, err)
+		case !wasHealthy && err == nil:
+			log.Printf("Database %s is healthy", replica.name)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CaughtUpTo returns the time up to which the healthy replicas have replayed
+// the commits of the primary: at the last check, they lagged at most maxLag.
+func (r *Replicas) CaughtUpTo() time.Time {
+	return time.Unix(0, r.checkedAt.Load()).Add(-r.maxLag)
+}
+
+// Monitor checks the replicas every interval until ctx is done.
+func (r *Replicas) Monitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			_ = r.Check(checkCtx)
+			cancel()
+		}
+	}
+}
+
+// Close closes the replica pools.
+func (r *Replicas) Close() error {
+	var errs []error
+	for _, replica := range r.replicas {
+		errs = append(errs, replica.db.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// replicationStatus is the state of a server as seen by a replica check.
+type replicationStatus struct {
+	// InRecovery is false on a server that is not a standby, such as the
+	// primary itself, which has no lag.
+	InRecovery bool
+	// Streaming reports a WAL receiver streaming from the primary.
+	Streaming bool
+	// Lag is the time since the last replayed transaction committed, or 0
+	// when all the WAL received has been replayed.
+	Lag time.Duration
+}
+
+// check returns an error when the replica does not answer, is not streaming
+// from the primary or lags more than maxLag.
+func (r *replica) check(ctx context.Context, maxLag time.Duration) error {
+	query := `
+		SELECT
+			pg_is_in_recovery(),
+			COALESCE((SELECT status = 'streaming' FROM pg_stat_wal_receiver), false),
+			CASE
+				WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+				ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+			END`
+	var status replicationStatus
+	var seconds float64
+	if err := r.db.QueryRowContext(ctx, query).Scan(&status.InRecovery, &status.Streaming, &seconds); err != nil {
+		return fmt.Errorf("failed to check replication lag: %w", err)
+	}
+	status.Lag = time.Duration(seconds * float64(time.Second))
+	return status.check(maxLag)
+}
+
+// check returns an error unless the server serves data at most maxLag old. A
+// standby that replayed all the WAL it received is caught up only while it is
+// streaming: one whose stream broke has nothing left to replay, however far
+// behind the primary it falls.
+func (s replicationStatus) check(maxLag time.Duration) error {
+	switch {
+	case !s.InRecovery:
+		return nil
+	case !s.Streaming:
+		return errors.New("not streaming WAL from the primary")
+	case s.Lag > maxLag:
+		return fmt.Errorf("replication lag %s exceeds %s", s.Lag.Round(time.Millisecond), maxLag)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,203 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/cache"
+)
+
+func TestReplicaConfigFromEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    ReplicaConfig
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			want: ReplicaConfig{MaxLag: 5 * time.Second, CheckInterval: 5 * time.Second},
+		},
+		{
+			name: "all settings",
+			env: map[string]string{
+				"DB_REPLICA_URLS":           "postgres://replica1/cupid, postgres://replica2/cupid,",
+				"DB_REPLICA_MAX_LAG":        "1s",
+				"DB_REPLICA_CHECK_INTERVAL": "10s",
+			},
+			want: ReplicaConfig{
+				URLs:          []string{"postgres://replica1/cupid", "postgres://replica2/cupid"},
+				MaxLag:        time.Second,
+				CheckInterval: 10 * time.Second,
+			},
+		},
+		{
+			name:    "invalid max lag",
+			env:     map[string]string{"DB_REPLICA_MAX_LAG": "0s"},
+			wantErr: `invalid DB_REPLICA_MAX_LAG "0s"`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			config, err := replicaConfigFromEnv(func(key string) string { return tc.env[key] })
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, config)
+		})
+	}
+}
+
+func TestReplicas_Reader(t *testing.T) {
+	t.Parallel()
+
+	dbs := []*DB{{}, {}, {}}
+	replicas := NewReplicas(dbs, time.Second)
+	assert.Nil(t, replicas.Reader(), "replicas are unhealthy until checked")
+
+	replicas.replicas[0].healthy.Store(true)
+	replicas.replicas[2].healthy.Store(true)
+	seen := map[*DB]int{}
+	for i := 0; i < 10; i++ {
+		seen[replicas.Reader()]++
+	}
+	assert.Equal(t, map[*DB]int{dbs[0]: 5, dbs[2]: 5}, seen, "healthy replicas share the reads")
+
+	assert.Nil(t, NewReplicas(nil, time.Second).Reader())
+}
+
+func TestHotelRepository_CacheLoadsAfterInvalidation(t *testing.T) {
+	t.Parallel()
+
+	const maxLag = 10 * time.Millisecond
+	primary, replica := &DB{}, &DB{}
+	replicas := NewReplicas([]*DB{replica}, maxLag)
+	replicas.replicas[0].healthy.Store(true)
+	repo := NewHotelRepository(primary)
+	repo.SetReplicas(replicas)
+
+	store := cache.NewMemoryStore(1<<10, 0)
+	hotels := cache.New[string](store, cache.HotelNamespace)
+	ctx := context.Background()
+	readFrom := func(key string) *DB {
+		var db *DB
+		_, _, err := hotels.GetOrLoad(ctx, key, func(ctx context.Context) (string, error) {
+			db = repo.reader(ctx)
+			return "v", nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, hotels.Delete(ctx, key))
+		return db
+	}
+	checked := func() {
+		time.Sleep(2 * maxLag)
+		replicas.checkedAt.Store(time.Now().UnixNano())
+	}
+
+	// Before a check shows the replicas caught up with the writes the store
+	// may have missed, cache loads go to the primary.
+	assert.Same(t, primary, readFrom("1"))
+	assert.Same(t, replica, repo.reader(ctx), "reads that are not cached go to replicas")
+
+	checked()
+	assert.Same(t, replica, readFrom("1"))
+
+	// A key invalidated since the check may not have reached the replica yet.
+	require.NoError(t, store.Invalidate(ctx, cache.Invalidation{HotelID: 2, Entity: cache.EntityHotel}))
+	assert.Same(t, primary, readFrom("2"))
+	assert.Same(t, replica, readFrom("3"))
+
+	checked()
+	assert.Same(t, replica, readFrom("2"))
+}
+
+func TestReplicationStatus_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  replicationStatus
+		wantErr string
+	}{
+		{"primary", replicationStatus{InRecovery: false}, ""},
+		{"caught up", replicationStatus{InRecovery: true, Streaming: true}, ""},
+		{"lagging within max", replicationStatus{InRecovery: true, Streaming: true, Lag: time.Second}, ""},
+		{"lagging", replicationStatus{InRecovery: true, Streaming: true, Lag: 3 * time.Second}, "replication lag 3s exceeds 2s"},
+		{"stream broken", replicationStatus{InRecovery: true, Streaming: false}, "not streaming WAL from the primary"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.status.check(2 * time.Second)
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestReplicas_Check(t *testing.T) {
+	t.Parallel()
+
+	config, err := ConfigFromEnv("cupid-test")
+	require.NoError(t, err)
+	healthy, err := open(config)
+	require.NoError(t, err)
+	defer healthy.Close()
+	closed, err := open(config)
+	require.NoError(t, err)
+	closed.Close()
+
+	ctx := context.Background()
+	replicas := NewReplicas([]*DB{healthy, closed}, time.Second)
+	err = replicas.Check(ctx)
+	assert.ErrorContains(t, err, "replica 2: failed to check replication lag")
+	assert.NotContains(t, err.Error(), "replica 1")
+	for i := 0; i < 4; i++ {
+		assert.Same(t, healthy, replicas.Reader())
+	}
+}
+
+func TestHotelRepository_ReadsFromReplicas(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	ctx := context.Background()
+	repo := NewHotelRepository(db)
+
+	config, err := ConfigFromEnv("cupid-test")
+	require.NoError(t, err)
+	replicaDB, err := open(config)
+	require.NoError(t, err)
+	replicas := NewReplicas([]*DB{replicaDB}, time.Second)
+	repo.SetReplicas(replicas)
+
+	property := createRandomProperty()
+	require.NoError(t, repo.StoreProperty(ctx, property))
+
+	require.NoError(t, replicas.Check(ctx))
+	hotel, err := repo.GetHotelByID(ctx, property.HotelID)
+	require.NoError(t, err)
+	assert.Equal(t, property.HotelName, hotel.HotelName)
+
+	// A replica that goes down is skipped after the next check.
+	replicaDB.Close()
+	assert.Error(t, replicas.Check(ctx))
+	hotel, err = repo.GetHotelByID(ctx, property.HotelID)
+	require.NoError(t, err)
+	assert.Equal(t, property.HotelName, hotel.HotelName)
+}
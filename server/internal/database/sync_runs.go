@@ -0,0 +1,279 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrSyncRunNotFound is returned when a sync run does not exist.
+var ErrSyncRunNotFound = errors.New("sync run not found")
+
+// Sync run statuses.
+const (
+	SyncRunRunning             = "running"
+	SyncRunCompleted           = "completed"
+	SyncRunCompletedWithErrors = "completed_with_errors"
+	SyncRunInterrupted         = "interrupted"
+)
+
+// Sync run item statuses. Pending and skipped items are picked up again when a
+// run is resumed; blocked items were not attempted because the content sync of
+// the hotel failed earlier in the same pipeline.
+const (
+	SyncItemPending   = "pending"
+	SyncItemSucceeded = "succeeded"
+	SyncItemFailed    = "failed"
+	SyncItemSkipped   = "skipped"
+	SyncItemBlocked   = "blocked"
+)
+
+// SyncRun is a data-sync batch run.
+type SyncRun struct {
+	ID           int64      `json:"id"`
+	EndpointType string     `json:"endpoint_type"`
+	Status       string     `json:"status"`
+	Total        int        `json:"total"`
+	Successful   int        `json:"successful"`
+	Failed       int        `json:"failed"`
+	Skipped      int        `json:"skipped"`
+	Blocked      int        `json:"blocked"`
+	ErrorText    string     `json:"error_text,omitempty"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// SyncRunItem is the outcome of one hotel in a sync run.
+type SyncRunItem struct {
+	RunID      int64      `json:"run_id"`
+	HotelID    int        `json:"hotel_id"`
+	Status     string     `json:"status"`
+	ErrorText  string     `json:"error_text,omitempty"`
+	HTTPStatus int        `json:"http_status,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+const syncRunColumns = `id, endpoint_type, status, total, successful, failed, skipped, blocked,
+	COALESCE(error_text, ''), started_at, finished_at`
+
+func scanSyncRun(row interface{ Scan(...any) error }) (*SyncRun, error) {
+	var run SyncRun
+	var finishedAt sql.NullTime
+	err := row.Scan(&run.ID, &run.EndpointType, &run.Status, &run.Total, &run.Successful,
+		&run.Failed, &run.Skipped, &run.Blocked, &run.ErrorText, &run.StartedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if finishedAt.Valid {
+		run.FinishedAt = &finishedAt.Time
+	}
+	return &run, nil
+}
+
+// CreateSyncRun records a new run over hotelIDs, with every hotel pending.
+func (r *HotelRepository) CreateSyncRun(ctx context.Context, endpointType string, hotelIDs []int) (*SyncRun, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				log.Printf("failed to rollback transaction: %v", rbErr)
+			}
+		}
+	}()
+
+	query := `INSERT INTO sync_runs (endpoint_type, total) VALUES ($1, $2) RETURNING ` + syncRunColumns
+	run, err := scanSyncRun(tx.QueryRowContext(ctx, query, endpointType, len(hotelIDs)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert sync run: %w", err)
+	}
+
+	itemsQuery := `
+		INSERT INTO sync_run_items (run_id, hotel_id, position)
+		SELECT $1, t.hotel_id, t.position
+		FROM unnest($2::int[]) WITH ORDINALITY AS t(hotel_id, position)
+		ON CONFLICT (run_id, hotel_id) DO NOTHING`
+	if _, err := tx.ExecContext(ctx, itemsQuery, run.ID, pq.Array(int64s(hotelIDs))); err != nil {
+		return nil, fmt.Errorf("failed to insert sync run items: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	return run, nil
+}
+
+// ResumeSyncRun marks a run as running again and returns the hotels it has
+// not completed yet, pending or skipped, in their original order.
+func (r *HotelRepository) ResumeSyncRun(ctx context.Context, runID int64) (*SyncRun, []int, error) {
+	query := `UPDATE sync_runs SET status = $2, finished_at = NULL WHERE id = $1 RETURNING ` + syncRunColumns
+	run, err := scanSyncRun(r.db.QueryRowContext(ctx, query, runID, SyncRunRunning))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrSyncRunNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resume sync run: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT hotel_id FROM sync_run_items
+		WHERE run_id = $1 AND status IN ($2, $3)
+		ORDER BY position`, runID, SyncItemPending, SyncItemSkipped)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query sync run items: %w", err)
+	}
+	defer rows.Close()
+
+	var hotelIDs []int
+	for rows.Next() {
+		var hotelID int
+		if err := rows.Scan(&hotelID); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan sync run item: %w", err)
+		}
+		hotelIDs = append(hotelIDs, hotelID)
+	}
+	return run, hotelIDs, rows.Err()
+}
+
+// RecordSyncRunItem stores the outcome of one hotel of a run.
+func (r *HotelRepository) RecordSyncRunItem(ctx context.Context, item SyncRunItem) error {
+	query := `
+		UPDATE sync_run_items SET
+			status = $3,
+			error_text = NULLIF($4, ''),
+			http_status = NULLIF($5, 0),
+			duration_ms = $6,
+			started_at = $7,
+			finished_at = $8
+		WHERE run_id = $1 AND hotel_id = $2`
+
+	_, err := r.db.ExecContext(ctx, query, item.RunID, item.HotelID, item.Status, item.ErrorText,
+		item.HTTPStatus, item.DurationMs, item.StartedAt, item.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record sync run item: %w", err)
+	}
+	return nil
+}
+
+// FinishSyncRun closes a run, deriving its counters and status from its items:
+// interrupted when hotels are left to sync, completed_with_errors when any
+// failed or were blocked.
+func (r *HotelRepository) FinishSyncRun(ctx context.Context, runID int64, errorText string) (*SyncRun, error) {
+	query := `
+		WITH finished AS (
+			UPDATE sync_runs r SET
+				successful = c.successful,
+				failed = c.failed,
+				skipped = c.remaining,
+				blocked = c.blocked,
+				status = CASE
+					WHEN c.remaining > 0 THEN '` + SyncRunInterrupted + `'
+					WHEN c.failed > 0 OR c.blocked > 0 THEN '` + SyncRunCompletedWithErrors + `'
+					ELSE '` + SyncRunCompleted + `'
+				END,
+				error_text = NULLIF($2, ''),
+				finished_at = NOW()
+			FROM (
+				SELECT
+					COUNT(*) FILTER (WHERE status = '` + SyncItemSucceeded + `') AS successful,
+					COUNT(*) FILTER (WHERE status = '` + SyncItemFailed + `') AS failed,
+					COUNT(*) FILTER (WHERE status = '` + SyncItemBlocked + `') AS blocked,
+					COUNT(*) FILTER (WHERE status IN ('` + SyncItemPending + `', '` + SyncItemSkipped + `')) AS remaining
+				FROM sync_run_items WHERE run_id = $1
+			) c
+			WHERE r.id = $1
+			RETURNING r.*
+		)
+		SELECT ` + syncRunColumns + ` FROM finished`
+
+	run, err := scanSyncRun(r.db.QueryRowContext(ctx, query, runID, errorText))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSyncRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to finish sync run: %w", err)
+	}
+	return run, nil
+}
+
+// ListSyncRuns returns runs, most recent first.
+func (r *HotelRepository) ListSyncRuns(ctx context.Context, limit, offset int) ([]SyncRun, error) {
+	query := `SELECT ` + syncRunColumns + ` FROM sync_runs ORDER BY started_at DESC, id DESC LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []SyncRun{}
+	for rows.Next() {
+		run, err := scanSyncRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sync run: %w", err)
+		}
+		runs = append(runs, *run)
+	}
+	return runs, rows.Err()
+}
+
+// GetSyncRun returns a single run.
+func (r *HotelRepository) GetSyncRun(ctx context.Context, runID int64) (*SyncRun, error) {
+	query := `SELECT ` + syncRunColumns + ` FROM sync_runs WHERE id = $1`
+
+	run, err := scanSyncRun(r.db.QueryRowContext(ctx, query, runID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSyncRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync run: %w", err)
+	}
+	return run, nil
+}
+
+// GetSyncRunItems returns the items of a run in run order, optionally filtered by status.
+func (r *HotelRepository) GetSyncRunItems(ctx context.Context, runID int64, status string) ([]SyncRunItem, error) {
+	query := `
+		SELECT run_id, hotel_id, status, COALESCE(error_text, ''), COALESCE(http_status, 0),
+			COALESCE(duration_ms, 0), started_at, finished_at
+		FROM sync_run_items
+		WHERE run_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY position`
+
+	rows, err := r.db.QueryContext(ctx, query, runID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync run items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []SyncRunItem{}
+	for rows.Next() {
+		var item SyncRunItem
+		var startedAt, finishedAt sql.NullTime
+		if err := rows.Scan(&item.RunID, &item.HotelID, &item.Status, &item.ErrorText, &item.HTTPStatus,
+			&item.DurationMs, &startedAt, &finishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan sync run item: %w", err)
+		}
+		if startedAt.Valid {
+			item.StartedAt = &startedAt.Time
+		}
+		if finishedAt.Valid {
+			item.FinishedAt = &finishedAt.Time
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotelRepository_SyncRuns(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := NewHotelRepository(db)
+	ctx := context.Background()
+
+	hotelIDs := []int{randomID(), randomID(), randomID()}
+	run, err := repo.CreateSyncRun(ctx, "content", hotelIDs)
+	require.NoError(t, err)
+	assert.Equal(t, SyncRunRunning, run.Status)
+	assert.Equal(t, 3, run.Total)
+
+	now := time.Now()
+	require.NoError(t, repo.RecordSyncRunItem(ctx, SyncRunItem{
+		RunID: run.ID, HotelID: hotelIDs[0], Status: SyncItemSucceeded, DurationMs: 120, StartedAt: &now, FinishedAt: &now,
+	}))
+	require.NoError(t, repo.RecordSyncRunItem(ctx, SyncRunItem{
+		RunID: run.ID, HotelID: hotelIDs[1], Status: SyncItemFailed, ErrorText: "boom", HTTPStatus: 503, StartedAt: &now, FinishedAt: &now,
+	}))
+
+	finished, err := repo.FinishSyncRun(ctx, run.ID, "context canceled")
+	require.NoError(t, err)
+	assert.Equal(t, SyncRunInterrupted, finished.Status)
+	assert.Equal(t, 1, finished.Successful)
+	assert.Equal(t, 1, finished.Failed)
+	assert.Equal(t, 1, finished.Skipped)
+	assert.Equal(t, "context canceled", finished.ErrorText)
+	assert.NotNil(t, finished.FinishedAt)
+
+	failed, err := repo.GetSyncRunItems(ctx, run.ID, SyncItemFailed)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, hotelIDs[1], failed[0].HotelID)
+	assert.Equal(t, 503, failed[0].HTTPStatus)
+
+	resumed, left, err := repo.ResumeSyncRun(ctx, run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, SyncRunRunning, resumed.Status)
+	assert.Equal(t, []int{hotelIDs[2]}, left)
+
+	require.NoError(t, repo.RecordSyncRunItem(ctx, SyncRunItem{RunID: run.ID, HotelID: hotelIDs[2], Status: SyncItemSucceeded}))
+	finished, err = repo.FinishSyncRun(ctx, run.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, SyncRunCompletedWithErrors, finished.Status)
+
+	_, _, err = repo.ResumeSyncRun(ctx, -1)
+	assert.ErrorIs(t, err, ErrSyncRunNotFound)
+}
+
+func TestHotelRepository_FinishSyncRunBlocked(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	repo := NewHotelRepository(db)
+	ctx := context.Background()
+
+	hotelIDs := []int{randomID(), randomID()}
+	run, err := repo.CreateSyncRun(ctx, "reviews", hotelIDs)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RecordSyncRunItem(ctx, SyncRunItem{RunID: run.ID, HotelID: hotelIDs[0], Status: SyncItemSucceeded}))
+	require.NoError(t, repo.RecordSyncRunItem(ctx, SyncRunItem{
+		RunID: run.ID, HotelID: hotelIDs[1], Status: SyncItemBlocked, ErrorText: "content sync failed",
+	}))
+
+	finished, err := repo.FinishSyncRun(ctx, run.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, SyncRunCompletedWithErrors, finished.Status)
+	assert.Equal(t, 1, finished.Successful)
+	assert.Equal(t, 1, finished.Blocked)
+	assert.Equal(t, 0, finished.Skipped)
+
+	_, left, err := repo.ResumeSyncRun(ctx, run.ID)
+	require.NoError(t, err)
+	assert.Empty(t, left, "blocked hotels are not resumed")
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+// ValidatorStore persists HTTP cache validators in the http_validators table.
+// It implements client.ValidatorStore.
+type ValidatorStore struct {
+	db *DB
+}
+
+func NewValidatorStore(db *DB) *ValidatorStore {
+	return &ValidatorStore{db: db}
+}
+
+func (s *ValidatorStore) GetValidators(ctx context.Context, url string) (client.Validators, bool, error) {
+	query := `SELECT COALESCE(etag, ''), COALESCE(last_modified, '') FROM http_validators WHERE url = $1`
+
+	var v client.Validators
+	err := s.db.QueryRowContext(ctx, query, url).Scan(&v.ETag, &v.LastModified)
+	if errors.Is(err, sql.ErrNoRows) {
+		return client.Validators{}, false, nil
+	}
+	if err != nil {
+		return client.Validators{}, false, fmt.Errorf("failed to query validators: %w", err)
+	}
+	return v, true, nil
+}
+
+func (s *ValidatorStore) SetValidators(ctx context.Context, url string, v client.Validators) error {
+	query := `
+		INSERT INTO http_validators (url, etag, last_modified, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (url) DO UPDATE SET
+			etag = EXCLUDED.etag,
+			last_modified = EXCLUDED.last_modified,
+			updated_at = NOW()`
+
+	if _, err := s.db.ExecContext(ctx, query, url, v.ETag, v.LastModified); err != nil {
+		return fmt.Errorf("failed to store validators: %w", err)
+	}
+	return nil
+}
+
+func (s *ValidatorStore) DeleteValidators(ctx context.Context, url string) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM http_validators WHERE url = $1", url); err != nil {
+		return fmt.Errorf("failed to delete validators: %w", err)
+	}
+	return nil
+}
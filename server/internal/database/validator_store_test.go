@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+func TestValidatorStore(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	store := NewValidatorStore(db)
+	ctx := context.Background()
+	url := fmt.Sprintf("https://content-api.cupid.travel/v3.0/property/%d", randomID())
+
+	_, ok, err := store.GetValidators(ctx, url)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	want := client.Validators{ETag: `"abc"`, LastModified: "Mon, 01 Sep 2025 10:00:00 GMT"}
+	require.NoError(t, store.SetValidators(ctx, url, want))
+
+	got, ok, err := store.GetValidators(ctx, url)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, want, got)
+
+	require.NoError(t, store.DeleteValidators(ctx, url))
+	_, ok, err = store.GetValidators(ctx, url)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
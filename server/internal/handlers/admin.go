@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+type catalogRequest struct {
+	HotelID  int   `json:"hotel_id"`
+	Active   *bool `json:"active"`
+	Priority int   `json:"priority"`
+}
+
+func (s *Server) listCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	entries, err := s.repository.ListCatalog(r.Context())
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"hotels": entries,
+		"count":  len(entries),
+	}); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// upsertCatalogHandler starts tracking a hotel. Posting an already tracked
+// hotel updates its active flag and priority; active defaults to true.
+func (s *Server) upsertCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	var req catalogRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.HotelID <= 0 {
+		http.Error(w, "hotel_id must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	entry := database.CatalogEntry{HotelID: req.HotelID, Active: true, Priority: req.Priority}
+	if req.Active != nil {
+		entry.Active = *req.Active
+	}
+
+	stored, err := s.repository.UpsertCatalogEntry(r.Context(), entry)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(stored); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (s *Server) deleteCatalogHandler(w http.ResponseWriter, r *http.Request) {
+	hotelID, err := strconv.Atoi(r.PathValue("hotelID"))
+	if err != nil {
+		http.Error(w, "Invalid hotel ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.repository.DeleteCatalogEntry(r.Context(), hotelID); err != nil {
+		if errors.Is(err, database.ErrCatalogEntryNotFound) {
+			http.Error(w, fmt.Sprintf("Hotel with ID %d is not tracked", hotelID), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// restoreHotelHandler serves a hotel soft-deleted after disappearing upstream
+// again, e.g. when the upstream 404s were a glitch.
+func (s *Server) restoreHotelHandler(w http.ResponseWriter, r *http.Request) {
+	hotelID, err := strconv.Atoi(r.PathValue("hotelID"))
+	if err != nil {
+		http.Error(w, "Invalid hotel ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.repository.RestoreHotel(r.Context(), hotelID); err != nil {
+		if errors.Is(err, database.ErrHotelNotFound) {
+			http.Error(w, fmt.Sprintf("Hotel with ID %d not found", hotelID), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listSyncRunsHandler(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	offset := 0
+
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	runs, err := s.repository.ListSyncRuns(r.Context(), limit, offset)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"runs":   runs,
+		"count":  len(runs),
+		"limit":  limit,
+		"offset": offset,
+	}); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// getSyncRunHandler returns a run with its per-hotel items. The status query
+// parameter filters items, e.g. ?status=failed.
+func (s *Server) getSyncRunHandler(w http.ResponseWriter, r *http.Request) {
+	runID, err := strconv.ParseInt(r.PathValue("runID"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid run ID format", http.StatusBadRequest)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", database.SyncItemPending, database.SyncItemSucceeded, database.SyncItemFailed, database.SyncItemSkipped, database.SyncItemBlocked:
+	default:
+		http.Error(w, "Unsupported status. Supported: pending, succeeded, failed, skipped, blocked", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	run, err := s.repository.GetSyncRun(ctx, runID)
+	if err != nil {
+		if errors.Is(err, database.ErrSyncRunNotFound) {
+			http.Error(w, fmt.Sprintf("Sync run %d not found", runID), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	items, err := s.repository.GetSyncRunItems(ctx, runID, status)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"run":   run,
+		"items": items,
+		"count": len(items),
+	}); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
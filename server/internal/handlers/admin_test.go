@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+const testAdminKey = "test-admin-key"
+
+// adminRequest returns a request authenticated with testAdminKey.
+func adminRequest(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Authorization", "Bearer "+testAdminKey)
+	return req
+}
+
+func TestServer_ListCatalogHandler(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	server := NewServer(mockRepo, &MockCache{}, "", testAdminKey)
+
+	entries := []database.CatalogEntry{{HotelID: 1641879, Active: true, Priority: 10}}
+	mockRepo.On("ListCatalog", mock.Anything).Return(entries, nil)
+
+	req := adminRequest("GET", "/admin/catalog", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Hotels []database.CatalogEntry `json:"hotels"`
+		Count  int                     `json:"count"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, 1641879, response.Hotels[0].HotelID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServer_AdminRequiresAdminKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		adminKey      string
+		authorization string
+		wantCode      int
+	}{
+		{name: "admin key unset", authorization: "Bearer anything", wantCode: http.StatusForbidden},
+		{name: "missing key", adminKey: testAdminKey, wantCode: http.StatusUnauthorized},
+		{name: "api key", adminKey: testAdminKey, authorization: "Bearer test-api-key", wantCode: http.StatusUnauthorized},
+		{name: "wrong key", adminKey: testAdminKey, authorization: "Bearer wrong", wantCode: http.StatusUnauthorized},
+	}
+
+	routes := []struct{ method, target string }{
+		{"GET", "/admin/catalog"},
+		{"POST", "/admin/catalog"},
+		{"DELETE", "/admin/catalog/1641879"},
+		{"POST", "/admin/hotels/1641879/restore"},
+		{"GET", "/admin/sync-runs"},
+		{"GET", "/admin/sync-runs/7"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := &MockRepository{}
+			server := NewServer(mockRepo, &MockCache{}, "test-api-key", tc.adminKey)
+
+			for _, route := range routes {
+				req := httptest.NewRequest(route.method, route.target, strings.NewReader(`{"hotel_id": 1641879}`))
+				if tc.authorization != "" {
+					req.Header.Set("Authorization", tc.authorization)
+				}
+				w := httptest.NewRecorder()
+				server.ServeHTTP(w, req)
+				assert.Equal(t, tc.wantCode, w.Code, "%s %s", route.method, route.target)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_UpsertCatalogHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		body     string
+		want     database.CatalogEntry
+		wantCode int
+	}{
+		{
+			name:     "defaults to active",
+			body:     `{"hotel_id": 1641879}`,
+			want:     database.CatalogEntry{HotelID: 1641879, Active: true},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "deactivate with priority",
+			body:     `{"hotel_id": 1641879, "active": false, "priority": 5}`,
+			want:     database.CatalogEntry{HotelID: 1641879, Active: false, Priority: 5},
+			wantCode: http.StatusOK,
+		},
+		{name: "missing hotel id", body: `{"priority": 5}`, wantCode: http.StatusBadRequest},
+		{name: "invalid json", body: `{`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := &MockRepository{}
+			server := NewServer(mockRepo, &MockCache{}, "", testAdminKey)
+			if tc.wantCode == http.StatusOK {
+				stored := tc.want
+				mockRepo.On("UpsertCatalogEntry", mock.Anything, tc.want).Return(&stored, nil)
+			}
+
+			req := adminRequest("POST", "/admin/catalog", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantCode, w.Code)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_DeleteCatalogHandler(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	server := NewServer(mockRepo, &MockCache{}, "", testAdminKey)
+
+	mockRepo.On("DeleteCatalogEntry", mock.Anything, 1641879).Return(nil)
+	mockRepo.On("DeleteCatalogEntry", mock.Anything, 999).Return(database.ErrCatalogEntryNotFound)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, adminRequest("DELETE", "/admin/catalog/1641879", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, adminRequest("DELETE", "/admin/catalog/999", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, adminRequest("DELETE", "/admin/catalog/abc", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServer_RestoreHotelHandler(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	server := NewServer(mockRepo, &MockCache{}, "", testAdminKey)
+
+	mockRepo.On("RestoreHotel", mock.Anything, 1641879).Return(nil)
+	mockRepo.On("RestoreHotel", mock.Anything, 999).Return(database.ErrHotelNotFound)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, adminRequest("POST", "/admin/hotels/1641879/restore", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, adminRequest("POST", "/admin/hotels/999/restore", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, adminRequest("POST", "/admin/hotels/abc/restore", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServer_ListSyncRunsHandler(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	server := NewServer(mockRepo, &MockCache{}, "", testAdminKey)
+
+	runs := []database.SyncRun{{ID: 7, EndpointType: "content", Status: database.SyncRunCompletedWithErrors, Total: 100, Successful: 98, Failed: 2}}
+	mockRepo.On("ListSyncRuns", mock.Anything, 5, 0).Return(runs, nil)
+
+	req := adminRequest("GET", "/admin/sync-runs?limit=5", nil)
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Runs  []database.SyncRun `json:"runs"`
+		Count int                `json:"count"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, 2, response.Runs[0].Failed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServer_GetSyncRunHandler(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	server := NewServer(mockRepo, &MockCache{}, "", testAdminKey)
+
+	run := &database.SyncRun{ID: 7, EndpointType: "content", Status: database.SyncRunCompletedWithErrors}
+	items := []database.SyncRunItem{{RunID: 7, HotelID: 1641879, Status: database.SyncItemFailed, HTTPStatus: 503, ErrorText: "service unavailable"}}
+	mockRepo.On("GetSyncRun", mock.Anything, int64(7)).Return(run, nil)
+	mockRepo.On("GetSyncRunItems", mock.Anything, int64(7), database.SyncItemFailed).Return(items, nil)
+	mockRepo.On("GetSyncRun", mock.Anything, int64(8)).Return(nil, database.ErrSyncRunNotFound)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, adminRequest("GET", "/admin/sync-runs/7?status=failed", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Run   database.SyncRun       `json:"run"`
+		Items []database.SyncRunItem `json:"items"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int64(7), response.Run.ID)
+	assert.Equal(t, 503, response.Items[0].HTTPStatus)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, adminRequest("GET", "/admin/sync-runs/8", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.ServeHTTP(w, adminRequest("GET", "/admin/sync-runs/7?status=bogus", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/cache"
+	"github.com/vrnvu/cupid/internal/client"
+	"github.com/vrnvu/cupid/internal/database"
+)
+
+// fakeServer is a database/sql connector answering the replica check as a
+// streaming standby, and hotel queries with a hotel named after the server.
+// It counts the hotel queries it answers.
+type fakeServer struct {
+	name string
+
+	mu      sync.Mutex
+	queries int
+}
+
+func (s *fakeServer) Connect(context.Context) (driver.Conn, error) { return fakeConn{s}, nil }
+func (s *fakeServer) Driver() driver.Driver                        { return nil }
+
+func (s *fakeServer) hotelQueries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries
+}
+
+type fakeConn struct{ server *fakeServer }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.server, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("no transactions") }
+
+type fakeStmt struct {
+	server *fakeServer
+	query  string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("no writes")
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	switch {
+	case strings.Contains(s.query, "pg_is_in_recovery"):
+		return &fakeRows{columns: 3, values: [][]driver.Value{{true, true, float64(0)}}}, nil
+	case strings.Contains(s.query, "FROM hotels WHERE hotel_id"):
+		s.server.mu.Lock()
+		s.server.queries++
+		s.server.mu.Unlock()
+		return &fakeRows{columns: 6, values: [][]driver.Value{{args[0], args[0], s.server.name, float64(4.5), int64(10), false}}}, nil
+	default:
+		return nil, errors.New("unexpected query")
+	}
+}
+
+type fakeRows struct {
+	columns int
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return make([]string, r.columns) }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestServer_CachedReadsFromReplica(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := cache.NewMemoryStore(1<<20, 0)
+
+	primaryServer, replicaServer := &fakeServer{name: "primary"}, &fakeServer{name: "replica"}
+	primary := &database.DB{DB: sql.OpenDB(primaryServer)}
+	defer primary.Close()
+	replica := &database.DB{DB: sql.OpenDB(replicaServer)}
+	replicas := database.NewReplicas([]*database.DB{replica}, time.Millisecond)
+	defer replicas.Close()
+
+	// The replicas are checked after the store was created, as the server
+	// starts, and lag behind by at most a millisecond.
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, replicas.Check(ctx))
+	repository := database.NewHotelRepository(primary)
+	repository.SetReplicas(replicas)
+	server := NewServer(repository, store, "", "")
+
+	getHotel := func() string {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/hotels/1", nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var hotel client.Property
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&hotel))
+		return hotel.HotelName
+	}
+
+	assert.Equal(t, "replica", getHotel(), "cache loads read from the replica")
+	assert.Equal(t, "replica", getHotel())
+	assert.Equal(t, 1, replicaServer.hotelQueries(), "the second request is served from the cache")
+	assert.Zero(t, primaryServer.hotelQueries())
+
+	// Right after a write, the replica may not have it yet.
+	require.NoError(t, store.Invalidate(ctx, cache.Invalidation{HotelID: 1, Entity: cache.EntityHotel}))
+	assert.Equal(t, "primary", getHotel())
+	assert.Equal(t, 1, replicaServer.hotelQueries())
+}
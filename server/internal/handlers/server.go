@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/vrnvu/cupid/internal/apicache"
 	"github.com/vrnvu/cupid/internal/cache"
 	"github.com/vrnvu/cupid/internal/client"
 	"github.com/vrnvu/cupid/internal/database"
@@ -17,16 +22,21 @@ import (
 
 type Server struct {
 	repository  database.Repository
-	cache       cache.ReviewCache
+	caches      apicache.Caches
 	apiKey      string
+	adminKey    string
 	rateLimiter *rate.Limiter
 }
 
-func NewServer(repository database.Repository, cache cache.ReviewCache, apiKey string) http.Handler {
+// NewServer returns the API handler. Responses are cached in store; a nil
+// store disables caching. The /api routes require apiKey when it is set; the
+// /admin routes always require adminKey and are disabled without one.
+func NewServer(repository database.Repository, store cache.Store, apiKey, adminKey string) http.Handler {
 	server := &Server{
 		repository:  repository,
-		cache:       cache,
+		caches:      apicache.New(store),
 		apiKey:      apiKey,
+		adminKey:    adminKey,
 		rateLimiter: rate.NewLimiter(rate.Every(time.Minute/10_000), 100), // 10_000 per minute, burst of 100
 	}
 
@@ -56,11 +66,42 @@ func NewServer(repository database.Repository, cache cache.ReviewCache, apiKey s
 		handler := telemetry.NewHandler(server.authenticateAndHandle(server.getHotelTranslationsHandler), "HotelTranslationsHandler")
 		handler.ServeHTTP(w, r)
 	})
+	mux.HandleFunc("GET /api/v1/hotels/{hotelID}/changes", func(w http.ResponseWriter, r *http.Request) {
+		handler := telemetry.NewHandler(server.authenticateAndHandle(server.getHotelChangesHandler), "HotelChangesHandler")
+		handler.ServeHTTP(w, r)
+	})
 	mux.HandleFunc("GET /api/v1/reviews/search", func(w http.ResponseWriter, r *http.Request) {
 		handler := telemetry.NewHandler(server.authenticateAndHandle(server.searchReviewsHandler), "SearchReviewsHandler")
 		handler.ServeHTTP(w, r)
 	})
 
+	// Admin endpoints
+	mux.HandleFunc("GET /admin/catalog", func(w http.ResponseWriter, r *http.Request) {
+		handler := telemetry.NewHandler(server.authenticateAdmin(server.listCatalogHandler), "ListCatalogHandler")
+		handler.ServeHTTP(w, r)
+	})
+	mux.HandleFunc("POST /admin/catalog", func(w http.ResponseWriter, r *http.Request) {
+		handler := telemetry.NewHandler(server.authenticateAdmin(server.upsertCatalogHandler), "UpsertCatalogHandler")
+		handler.ServeHTTP(w, r)
+	})
+	mux.HandleFunc("DELETE /admin/catalog/{hotelID}", func(w http.ResponseWriter, r *http.Request) {
+		handler := telemetry.NewHandler(server.authenticateAdmin(server.deleteCatalogHandler), "DeleteCatalogHandler")
+		handler.ServeHTTP(w, r)
+	})
+	mux.HandleFunc("POST /admin/hotels/{hotelID}/restore", func(w http.ResponseWriter, r *http.Request) {
+		handler := telemetry.NewHandler(server.authenticateAdmin(server.restoreHotelHandler), "RestoreHotelHandler")
+		handler.ServeHTTP(w, r)
+	})
+
+	mux.HandleFunc("GET /admin/sync-runs", func(w http.ResponseWriter, r *http.Request) {
+		handler := telemetry.NewHandler(server.authenticateAdmin(server.listSyncRunsHandler), "ListSyncRunsHandler")
+		handler.ServeHTTP(w, r)
+	})
+	mux.HandleFunc("GET /admin/sync-runs/{runID}", func(w http.ResponseWriter, r *http.Request) {
+		handler := telemetry.NewHandler(server.authenticateAdmin(server.getSyncRunHandler), "SyncRunHandler")
+		handler.ServeHTTP(w, r)
+	})
+
 	return mux
 }
 
@@ -72,29 +113,58 @@ func (s *Server) authenticateAndHandle(handler http.HandlerFunc) http.HandlerFun
 			return
 		}
 
-		if s.apiKey != "" {
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				http.Error(w, "Authorization header required", http.StatusUnauthorized)
-				return
-			}
+		if s.apiKey != "" && !checkBearer(w, r, s.apiKey) {
+			return
+		}
 
-			if len(authHeader) < 7 || authHeader[:7] != "Bearer " {
-				http.Error(w, "Invalid authorization format. Use 'Bearer <api-key>'", http.StatusUnauthorized)
-				return
-			}
+		handler(w, r)
+	}
+}
 
-			apiKey := authHeader[7:] // Remove "Bearer " prefix
-			if apiKey != s.apiKey {
-				http.Error(w, "Invalid API key", http.StatusUnauthorized)
-				return
-			}
+// authenticateAdmin wraps admin handlers with admin key authentication and
+// rate limiting. Unlike the API key, the admin key is required: without one,
+// admin requests are refused.
+func (s *Server) authenticateAdmin(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.rateLimiter.Allow() {
+			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		if s.adminKey == "" {
+			http.Error(w, "Admin API disabled: ADMIN_API_KEY is not set", http.StatusForbidden)
+			return
+		}
+		if !checkBearer(w, r, s.adminKey) {
+			return
 		}
 
 		handler(w, r)
 	}
 }
 
+// checkBearer reports whether the request carries key as a bearer token,
+// answering 401 when it does not.
+func checkBearer(w http.ResponseWriter, r *http.Request, key string) bool {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header required", http.StatusUnauthorized)
+		return false
+	}
+
+	if len(authHeader) < 7 || authHeader[:7] != "Bearer " {
+		http.Error(w, "Invalid authorization format. Use 'Bearer <api-key>'", http.StatusUnauthorized)
+		return false
+	}
+
+	token := authHeader[7:] // Remove "Bearer " prefix
+	if subtle.ConstantTimeCompare([]byte(token), []byte(key)) != 1 {
+		http.Error(w, "Invalid API key", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
 func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -138,7 +208,9 @@ func (s *Server) getHotelsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	hotels, err := s.repository.GetHotels(ctx, limit, offset)
+	hotels, _, err := s.caches.Hotels.GetOrLoad(ctx, cache.Key(limit, offset), func(ctx context.Context) ([]client.Property, error) {
+		return s.repository.GetHotels(ctx, limit, offset)
+	})
 	if err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
@@ -171,12 +243,22 @@ func (s *Server) getHotelHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	hotel, err := s.repository.GetHotelByID(ctx, hotelID)
+	hotel, _, err := s.caches.Hotel.GetOrLoad(ctx, cache.Key(hotelID), func(ctx context.Context) (client.Property, error) {
+		hotel, err := s.repository.GetHotelByID(ctx, hotelID)
+		if err != nil {
+			return client.Property{}, err
+		}
+		return *hotel, nil
+	})
 	if err != nil {
 		if errors.Is(err, database.ErrHotelNotFound) {
 			http.Error(w, fmt.Sprintf("Hotel with ID %d not found", hotelID), http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, database.ErrHotelGone) {
+			http.Error(w, fmt.Sprintf("Hotel with ID %d has been removed", hotelID), http.StatusGone)
+			return
+		}
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -203,31 +285,12 @@ func (s *Server) getHotelReviewsHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	ctx := r.Context()
-	var reviews []client.Review
-	var fromCache bool
-
-	if s.cache != nil {
-		cachedReviews, err := s.cache.GetReviews(ctx, hotelID)
-		if err == nil && cachedReviews != nil {
-			reviews = cachedReviews
-			fromCache = true
-		}
-	}
-
-	if !fromCache {
-		reviews, err = s.repository.GetHotelReviews(ctx, hotelID)
-		if err != nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
-			return
-		}
-
-		if s.cache != nil {
-			cacheErr := s.cache.SetReviews(ctx, hotelID, reviews, 5*time.Minute)
-			if cacheErr != nil {
-				// Log cache error but don't fail the request
-				fmt.Printf("Warning: Failed to cache reviews for hotel %d: %v\n", hotelID, cacheErr)
-			}
-		}
+	reviews, fromCache, err := s.caches.Reviews.GetOrLoad(ctx, cache.Key(hotelID), func(ctx context.Context) ([]client.Review, error) {
+		return s.repository.GetHotelReviews(ctx, hotelID)
+	})
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -264,14 +327,15 @@ func (s *Server) getHotelTranslationsHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Validate language code
-	validLanguages := map[string]bool{"fr": true, "es": true, "en": true}
-	if !validLanguages[languageCode] {
-		http.Error(w, "Unsupported language code. Supported: fr, es, en", http.StatusBadRequest)
+	if !slices.Contains(client.Languages, languageCode) {
+		http.Error(w, "Unsupported language code. Supported: "+strings.Join(client.Languages, ", "), http.StatusBadRequest)
 		return
 	}
 
 	ctx := r.Context()
-	translations, err := s.repository.GetHotelTranslations(ctx, hotelID, languageCode)
+	translations, _, err := s.caches.Translations.GetOrLoad(ctx, cache.Key(hotelID, languageCode), func(ctx context.Context) ([]client.Translation, error) {
+		return s.repository.GetHotelTranslations(ctx, hotelID, languageCode)
+	})
 	if err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
@@ -290,6 +354,50 @@ func (s *Server) getHotelTranslationsHandler(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+func (s *Server) getHotelChangesHandler(w http.ResponseWriter, r *http.Request) {
+	hotelIDStr := r.PathValue("hotelID")
+	if hotelIDStr == "" {
+		http.Error(w, "Invalid hotel ID", http.StatusBadRequest)
+		return
+	}
+
+	hotelID, err := strconv.Atoi(hotelIDStr)
+	if err != nil {
+		http.Error(w, "Invalid hotel ID format", http.StatusBadRequest)
+		return
+	}
+
+	limit := 20
+	offset := 0
+
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	ctx := r.Context()
+	changes, err := s.repository.GetHotelChanges(ctx, hotelID, limit, offset)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{
+		"hotel_id": hotelID,
+		"changes":  changes,
+		"count":    len(changes),
+		"limit":    limit,
+		"offset":   offset,
+	}); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
 func (s *Server) searchReviewsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -18,7 +18,7 @@ import (
 )
 
 // setupTestInfrastructure creates real database and cache connections for integration testing
-func setupTestInfrastructure(t *testing.T) (*database.DB, cache.ReviewCache, *database.HotelRepository) {
+func setupTestInfrastructure(t *testing.T) (*database.DB, *cache.RedisCache, *database.HotelRepository) {
 	t.Helper()
 
 	// Setup database
@@ -86,7 +86,7 @@ func TestServer_GetHotelsHandler_Integration(t *testing.T) {
 	t.Parallel()
 
 	_, cache, repo := setupTestInfrastructure(t)
-	server := NewServer(repo, cache, "")
+	server := NewServer(repo, cache, "", "")
 
 	tests := []struct {
 		name           string
@@ -150,7 +150,7 @@ func TestServer_GetHotelHandler_Integration(t *testing.T) {
 	t.Parallel()
 
 	_, cache, repo := setupTestInfrastructure(t)
-	server := NewServer(repo, cache, "")
+	server := NewServer(repo, cache, "", "")
 
 	tests := []struct {
 		name           string
@@ -198,7 +198,7 @@ func TestServer_GetHotelReviewsHandler_Integration(t *testing.T) {
 	t.Parallel()
 
 	_, cache, repo := setupTestInfrastructure(t)
-	server := NewServer(repo, cache, "")
+	server := NewServer(repo, cache, "", "")
 
 	tests := []struct {
 		name           string
@@ -231,7 +231,7 @@ func TestServer_GetHotelTranslationsHandler_Integration(t *testing.T) {
 	t.Parallel()
 
 	_, cache, repo := setupTestInfrastructure(t)
-	server := NewServer(repo, cache, "")
+	server := NewServer(repo, cache, "", "")
 
 	tests := []struct {
 		name           string
@@ -284,7 +284,7 @@ func TestServer_HealthHandler_Integration(t *testing.T) {
 	t.Parallel()
 
 	_, cache, repo := setupTestInfrastructure(t)
-	server := NewServer(repo, cache, "")
+	server := NewServer(repo, cache, "", "")
 
 	t.Run("HealthCheckWithDatabase", func(t *testing.T) {
 		t.Parallel()
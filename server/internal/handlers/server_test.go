@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/cache"
 	"github.com/vrnvu/cupid/internal/client"
 	"github.com/vrnvu/cupid/internal/database"
 )
@@ -60,6 +63,14 @@ func (m *MockRepository) GetHotelTranslations(ctx context.Context, hotelID int,
 	return args.Get(0).([]client.Translation), args.Error(1)
 }
 
+func (m *MockRepository) GetHotelChanges(ctx context.Context, hotelID, limit, offset int) ([]database.HotelChange, error) {
+	args := m.Called(ctx, hotelID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]database.HotelChange), args.Error(1)
+}
+
 func (m *MockRepository) SearchReviewsByVector(ctx context.Context, queryEmbedding []float64, limit int, threshold float64) ([]client.Review, error) {
 	args := m.Called(ctx, queryEmbedding, limit, threshold)
 	return args.Get(0).([]client.Review), args.Error(1)
@@ -70,41 +81,150 @@ func (m *MockRepository) GetReviewsNeedingEmbeddings(ctx context.Context, limit
 	return args.Get(0).([]int), args.Error(1)
 }
 
-func (m *MockRepository) Ping(ctx context.Context) error {
+func (m *MockRepository) GetTopHotelIDs(ctx context.Context, limit int) ([]int, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockRepository) GetCatalogHotelIDs(ctx context.Context, activeOnly bool) ([]int, error) {
+	args := m.Called(ctx, activeOnly)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockRepository) ListCatalog(ctx context.Context) ([]database.CatalogEntry, error) {
 	args := m.Called(ctx)
-	return args.Error(0)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]database.CatalogEntry), args.Error(1)
 }
 
-type MockCache struct {
-	mock.Mock
+func (m *MockRepository) UpsertCatalogEntry(ctx context.Context, entry database.CatalogEntry) (*database.CatalogEntry, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*database.CatalogEntry), args.Error(1)
 }
 
-func (m *MockCache) GetReviews(ctx context.Context, hotelID int) ([]client.Review, error) {
+func (m *MockRepository) DeleteCatalogEntry(ctx context.Context, hotelID int) error {
 	args := m.Called(ctx, hotelID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListSyncRuns(ctx context.Context, limit, offset int) ([]database.SyncRun, error) {
+	args := m.Called(ctx, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]client.Review), args.Error(1)
+	return args.Get(0).([]database.SyncRun), args.Error(1)
 }
 
-func (m *MockCache) SetReviews(ctx context.Context, hotelID int, reviews []client.Review, ttl time.Duration) error {
-	args := m.Called(ctx, hotelID, reviews, ttl)
-	return args.Error(0)
+func (m *MockRepository) GetSyncRun(ctx context.Context, runID int64) (*database.SyncRun, error) {
+	args := m.Called(ctx, runID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*database.SyncRun), args.Error(1)
+}
+
+func (m *MockRepository) GetSyncRunItems(ctx context.Context, runID int64, status string) ([]database.SyncRunItem, error) {
+	args := m.Called(ctx, runID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]database.SyncRunItem), args.Error(1)
 }
 
-func (m *MockCache) DeleteReviews(ctx context.Context, hotelID int) error {
+func (m *MockRepository) RestoreHotel(ctx context.Context, hotelID int) error {
 	args := m.Called(ctx, hotelID)
 	return args.Error(0)
 }
 
-func (m *MockCache) Ping(ctx context.Context) error {
+func (m *MockRepository) Ping(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
 }
 
-func (m *MockCache) Close() error {
-	args := m.Called()
-	return args.Error(0)
+// MockCache is an in-memory cache.Store. When err is set, every call fails
+// with it.
+type MockCache struct {
+	mu      sync.Mutex
+	items   map[string][]byte
+	indexes map[string][]string
+	err     error
+}
+
+func (m *MockCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return nil, false, m.err
+	}
+	value, ok := m.items[key]
+	return value, ok, nil
+}
+
+func (m *MockCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if m.items == nil {
+		m.items = make(map[string][]byte)
+	}
+	m.items[key] = value
+	return nil
+}
+
+func (m *MockCache) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	for _, key := range keys {
+		delete(m.items, key)
+	}
+	return nil
+}
+
+func (m *MockCache) AddToIndex(_ context.Context, index, key string, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	if m.indexes == nil {
+		m.indexes = make(map[string][]string)
+	}
+	m.indexes[index] = append(m.indexes[index], key)
+	return nil
+}
+
+func (m *MockCache) DeleteIndex(_ context.Context, index string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	for _, key := range m.indexes[index] {
+		delete(m.items, key)
+	}
+	delete(m.indexes, index)
+	return nil
+}
+
+// has reports whether key is cached.
+func (m *MockCache) has(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.items[key]
+	return ok
 }
 
 func TestNewServer(t *testing.T) {
@@ -114,7 +234,7 @@ func TestNewServer(t *testing.T) {
 	mockCache := &MockCache{}
 	apiKey := "test-api-key"
 
-	server := NewServer(mockRepo, mockCache, apiKey)
+	server := NewServer(mockRepo, mockCache, apiKey, "")
 	assert.NotNil(t, server)
 }
 
@@ -123,7 +243,7 @@ func TestServer_HealthHandler_NoAuth(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/health", nil)
 	w := httptest.NewRecorder()
@@ -148,7 +268,7 @@ func TestServer_HealthHandler_DatabaseError(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/health", nil)
 	w := httptest.NewRecorder()
@@ -167,7 +287,7 @@ func TestServer_Authentication_ValidAPIKey(t *testing.T) {
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
 	apiKey := "valid-api-key" //nolint:gosec // This is a test value, not a real credential
-	server := NewServer(mockRepo, mockCache, apiKey)
+	server := NewServer(mockRepo, mockCache, apiKey, "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels", nil)
 	req.Header.Set("Authorization", "Bearer valid-api-key")
@@ -187,7 +307,7 @@ func TestServer_Authentication_MissingAPIKey(t *testing.T) {
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
 	apiKey := "required-api-key"
-	server := NewServer(mockRepo, mockCache, apiKey)
+	server := NewServer(mockRepo, mockCache, apiKey, "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels", nil)
 	w := httptest.NewRecorder()
@@ -204,7 +324,7 @@ func TestServer_Authentication_InvalidFormat(t *testing.T) {
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
 	apiKey := "required-api-key"
-	server := NewServer(mockRepo, mockCache, apiKey)
+	server := NewServer(mockRepo, mockCache, apiKey, "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels", nil)
 	req.Header.Set("Authorization", "InvalidFormat")
@@ -222,7 +342,7 @@ func TestServer_Authentication_WrongAPIKey(t *testing.T) {
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
 	apiKey := "correct-api-key"
-	server := NewServer(mockRepo, mockCache, apiKey)
+	server := NewServer(mockRepo, mockCache, apiKey, "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels", nil)
 	req.Header.Set("Authorization", "Bearer wrong-api-key")
@@ -239,7 +359,7 @@ func TestServer_Authentication_NoAuthRequired(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "") // No API key required
+	server := NewServer(mockRepo, mockCache, "", "") // No API key required
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels", nil)
 	w := httptest.NewRecorder()
@@ -256,7 +376,7 @@ func TestServer_RateLimiting(t *testing.T) {
 	t.Parallel()
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels", nil)
 	w := httptest.NewRecorder()
@@ -274,7 +394,7 @@ func TestServer_GetHotelsHandler_Success(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels", nil)
 	w := httptest.NewRecorder()
@@ -307,7 +427,7 @@ func TestServer_GetHotelsHandler_WithPagination(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels?limit=10&offset=20", nil)
 	w := httptest.NewRecorder()
@@ -334,7 +454,7 @@ func TestServer_GetHotelsHandler_DatabaseError(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels", nil)
 	w := httptest.NewRecorder()
@@ -352,7 +472,7 @@ func TestServer_GetHotelHandler_Success(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels/123", nil)
 	w := httptest.NewRecorder()
@@ -378,7 +498,7 @@ func TestServer_GetHotelHandler_NotFound(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels/999", nil)
 	w := httptest.NewRecorder()
@@ -393,12 +513,32 @@ func TestServer_GetHotelHandler_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestServer_GetHotelHandler_Gone(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	mockCache := &MockCache{}
+	server := NewServer(mockRepo, mockCache, "", "")
+
+	req := httptest.NewRequest("GET", "/api/v1/hotels/998", nil)
+	w := httptest.NewRecorder()
+
+	mockRepo.On("GetHotelByID", mock.Anything, 998).Return(nil, database.ErrHotelGone)
+
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Contains(t, w.Body.String(), "Hotel with ID 998 has been removed")
+
+	mockRepo.AssertExpectations(t)
+}
+
 func TestServer_GetHotelHandler_InvalidID(t *testing.T) {
 	t.Parallel()
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels/invalid", nil)
 	w := httptest.NewRecorder()
@@ -416,7 +556,7 @@ func TestServer_GetHotelReviewsHandler_Success(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels/123/reviews", nil)
 	w := httptest.NewRecorder()
@@ -426,9 +566,7 @@ func TestServer_GetHotelReviewsHandler_Success(t *testing.T) {
 		{ID: 2, Rating: 4, Title: "Good experience", Content: "Nice stay"},
 	}
 
-	mockCache.On("GetReviews", mock.Anything, 123).Return(nil, assert.AnError)
 	mockRepo.On("GetHotelReviews", mock.Anything, 123).Return(expectedReviews, nil)
-	mockCache.On("SetReviews", mock.Anything, 123, expectedReviews, mock.Anything).Return(nil)
 
 	server.ServeHTTP(w, req)
 
@@ -441,9 +579,9 @@ func TestServer_GetHotelReviewsHandler_Success(t *testing.T) {
 	assert.Equal(t, float64(123), response["hotel_id"])
 	assert.Equal(t, float64(2), response["count"])
 	assert.Equal(t, false, response["from_cache"])
+	assert.True(t, mockCache.has("reviews:hotel:123"))
 
 	mockRepo.AssertExpectations(t)
-	mockCache.AssertExpectations(t)
 }
 
 func TestServer_GetHotelReviewsHandler_FromCache(t *testing.T) {
@@ -451,7 +589,7 @@ func TestServer_GetHotelReviewsHandler_FromCache(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels/123/reviews", nil)
 	w := httptest.NewRecorder()
@@ -460,7 +598,8 @@ func TestServer_GetHotelReviewsHandler_FromCache(t *testing.T) {
 		{ID: 1, Rating: 5, Title: "Great hotel!", Content: "Excellent experience"},
 	}
 
-	mockCache.On("GetReviews", mock.Anything, 123).Return(expectedReviews, nil)
+	reviewsCache := cache.New[[]client.Review](mockCache, cache.ReviewsNamespace)
+	require.NoError(t, reviewsCache.Set(context.Background(), cache.Key(123), expectedReviews))
 
 	server.ServeHTTP(w, req)
 
@@ -473,7 +612,108 @@ func TestServer_GetHotelReviewsHandler_FromCache(t *testing.T) {
 	assert.Equal(t, true, response["from_cache"])
 	assert.Equal(t, float64(1), response["count"])
 
-	mockCache.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServer_GetHotelReviewsHandler_CacheError(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	mockCache := &MockCache{err: assert.AnError}
+	server := NewServer(mockRepo, mockCache, "", "")
+
+	expectedReviews := []client.Review{{ID: 1, Rating: 5, Title: "Great hotel!"}}
+	mockRepo.On("GetHotelReviews", mock.Anything, 123).Return(expectedReviews, nil)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/hotels/123/reviews", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, false, response["from_cache"])
+	assert.Equal(t, float64(1), response["count"])
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServer_CachesResponses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		path  string
+		key   string
+		setup func(*MockRepository)
+	}{
+		{
+			name: "hotel",
+			path: "/api/v1/hotels/123",
+			key:  "hotel:123",
+			setup: func(m *MockRepository) {
+				m.On("GetHotelByID", mock.Anything, 123).Return(&client.Property{HotelID: 123, HotelName: "Test Hotel"}, nil).Once()
+			},
+		},
+		{
+			name: "hotel list",
+			path: "/api/v1/hotels?limit=10&offset=20",
+			key:  "hotels:page:10:20",
+			setup: func(m *MockRepository) {
+				m.On("GetHotels", mock.Anything, 10, 20).Return([]client.Property{{HotelID: 1}}, nil).Once()
+			},
+		},
+		{
+			name: "translations",
+			path: "/api/v1/hotels/123/translations/fr",
+			key:  "translations:hotel:123:fr",
+			setup: func(m *MockRepository) {
+				m.On("GetHotelTranslations", mock.Anything, 123, "fr").Return([]client.Translation{{FieldName: "name", TranslatedText: "Hôtel"}}, nil).Once()
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := &MockRepository{}
+			mockCache := &MockCache{}
+			server := NewServer(mockRepo, mockCache, "", "")
+			tc.setup(mockRepo)
+
+			var bodies []string
+			for i := 0; i < 2; i++ {
+				w := httptest.NewRecorder()
+				server.ServeHTTP(w, httptest.NewRequest("GET", tc.path, nil))
+				require.Equal(t, http.StatusOK, w.Code)
+				bodies = append(bodies, w.Body.String())
+			}
+
+			assert.Equal(t, bodies[0], bodies[1])
+			assert.True(t, mockCache.has(tc.key))
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_GetHotelHandler_DoesNotCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	mockCache := &MockCache{}
+	server := NewServer(mockRepo, mockCache, "", "")
+
+	mockRepo.On("GetHotelByID", mock.Anything, 999).Return(nil, database.ErrHotelNotFound).Twice()
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/hotels/999", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	}
+	assert.False(t, mockCache.has("hotel:999"))
+
+	mockRepo.AssertExpectations(t)
 }
 
 func TestServer_GetHotelTranslationsHandler_Success(t *testing.T) {
@@ -481,7 +721,7 @@ func TestServer_GetHotelTranslationsHandler_Success(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels/123/translations/fr", nil)
 	w := httptest.NewRecorder()
@@ -507,12 +747,65 @@ func TestServer_GetHotelTranslationsHandler_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestServer_GetHotelChangesHandler_Success(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	mockCache := &MockCache{}
+	server := NewServer(mockRepo, mockCache, "", "")
+
+	req := httptest.NewRequest("GET", "/api/v1/hotels/123/changes?limit=5", nil)
+	w := httptest.NewRecorder()
+
+	expectedChanges := []database.HotelChange{{
+		ID:      1,
+		HotelID: 123,
+		Summary: "rating 8.3→8.4; 2 photos added",
+		Changes: []client.PropertyChange{{Field: "rating", Op: client.ChangeModified, Old: 8.3, New: 8.4}},
+	}}
+	mockRepo.On("GetHotelChanges", mock.Anything, 123, 5, 0).Return(expectedChanges, nil)
+
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		HotelID int                    `json:"hotel_id"`
+		Changes []database.HotelChange `json:"changes"`
+		Count   int                    `json:"count"`
+	}
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, 123, response.HotelID)
+	assert.Equal(t, 1, response.Count)
+	assert.Equal(t, "rating 8.3→8.4; 2 photos added", response.Changes[0].Summary)
+	assert.Equal(t, "rating", response.Changes[0].Changes[0].Field)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestServer_GetHotelChangesHandler_InvalidID(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	mockCache := &MockCache{}
+	server := NewServer(mockRepo, mockCache, "", "")
+
+	req := httptest.NewRequest("GET", "/api/v1/hotels/invalid/changes", nil)
+	w := httptest.NewRecorder()
+
+	server.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestServer_GetHotelTranslationsHandler_InvalidLanguage(t *testing.T) {
 	t.Parallel()
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/hotels/123/translations/xx", nil)
 	w := httptest.NewRecorder()
@@ -530,7 +823,7 @@ func TestServer_SearchReviewsHandler_Success(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/reviews/search?q=great&limit=5&threshold=0.8", nil)
 	w := httptest.NewRecorder()
@@ -554,7 +847,7 @@ func TestServer_SearchReviewsHandler_MissingQuery(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/reviews/search", nil)
 	w := httptest.NewRecorder()
@@ -570,7 +863,7 @@ func TestServer_SearchReviewsHandler_InvalidLimit(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/reviews/search?q=test&limit=invalid", nil)
 	w := httptest.NewRecorder()
@@ -592,7 +885,7 @@ func TestServer_SearchReviewsHandler_LimitExceedsMax(t *testing.T) {
 
 	mockRepo := &MockRepository{}
 	mockCache := &MockCache{}
-	server := NewServer(mockRepo, mockCache, "")
+	server := NewServer(mockRepo, mockCache, "", "")
 
 	req := httptest.NewRequest("GET", "/api/v1/reviews/search?q=test&limit=150", nil)
 	w := httptest.NewRecorder()
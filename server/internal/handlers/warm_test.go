@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/apicache"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+func TestServer_ServesWarmedCache(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockRepository{}
+	mockCache := &MockCache{}
+	ctx := context.Background()
+
+	mockRepo.On("GetHotelByID", mock.Anything, 1).Return(&client.Property{HotelID: 1, HotelName: "Warm"}, nil).Once()
+	mockRepo.On("GetHotelReviews", mock.Anything, 1).Return([]client.Review{{ID: 10}}, nil).Once()
+	for _, lang := range client.Languages {
+		mockRepo.On("GetHotelTranslations", mock.Anything, 1, lang).Return([]client.Translation{}, nil).Once()
+	}
+
+	warmed, err := apicache.Warm(ctx, mockRepo, mockCache, []int{1}, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, 1, warmed)
+
+	// The requests are served from the warmed cache.
+	server := NewServer(mockRepo, mockCache, "", "")
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/hotels/1/reviews", nil))
+	assert.Contains(t, w.Body.String(), `"from_cache":true`)
+	mockRepo.AssertExpectations(t)
+}
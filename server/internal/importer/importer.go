@@ -0,0 +1,416 @@
+// Package importer reads Cupid API payloads from local files: single JSON
+// documents, concatenated JSON or NDJSON streams, and wiremock mappings.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+// Kind is the type of payload of a record.
+type Kind string
+
+// Payload kinds.
+const (
+	KindProperty     Kind = "property"
+	KindReviews      Kind = "reviews"
+	KindTranslations Kind = "translations"
+)
+
+// ParseKind parses a kind flag; an empty string or "auto" detects the kind of every record.
+func ParseKind(s string) (Kind, error) {
+	switch Kind(s) {
+	case "", "auto":
+		return "", nil
+	case KindProperty, KindReviews, KindTranslations:
+		return Kind(s), nil
+	default:
+		return "", fmt.Errorf("invalid payload type: %s. Must be one of: auto, property, reviews, translations", s)
+	}
+}
+
+// Options controls how payloads are interpreted.
+type Options struct {
+	// Kind forces the payload kind instead of detecting it.
+	Kind Kind
+	// HotelID assigns reviews and translations to this hotel.
+	HotelID int
+	// NDJSON reads one JSON value per line, so a broken line does not stop the stream.
+	NDJSON bool
+}
+
+// Record is one payload read from a stream, holding the reviews or
+// translations of a single hotel.
+type Record struct {
+	// Source locates the record: the stream name and its line (NDJSON) or value number.
+	Source       string
+	Kind         Kind
+	HotelID      int
+	Property     *client.Property
+	Reviews      []client.Review
+	Translations []client.Translation
+	// Skipped explains why a payload was ignored, such as a wiremock mapping of an error response.
+	Skipped string
+	// Err is set when the payload could not be parsed or failed validation.
+	Err error
+}
+
+// Decode reads every JSON value of r and calls fn with the records it holds.
+// Parse and validation failures are reported as records with Err set; Decode
+// only fails when r cannot be read or fn returns an error.
+func Decode(r io.Reader, name string, opts Options, fn func(Record) error) error {
+	if opts.NDJSON {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			value := bytes.TrimSpace(scanner.Bytes())
+			if len(value) == 0 || value[0] == '#' {
+				continue
+			}
+			if err := emit(decodeValue(value, fmt.Sprintf("%s:%d", name, line), opts), fn); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+	}
+
+	dec := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		var value json.RawMessage
+		err := dec.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		source := fmt.Sprintf("%s:%d", name, n)
+		if err != nil {
+			// The decoder cannot resynchronise after a syntax error, so the rest of the stream is lost.
+			return fn(Record{Source: source, Err: fmt.Errorf("failed to decode JSON: %w", err)})
+		}
+		if err := emit(decodeValue(value, source, opts), fn); err != nil {
+			return err
+		}
+	}
+}
+
+func emit(records []Record, fn func(Record) error) error {
+	for _, record := range records {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapping is the part of a wiremock stub mapping that holds the payload.
+type mapping struct {
+	Request struct {
+		URL            string `json:"url"`
+		URLPath        string `json:"urlPath"`
+		URLPattern     string `json:"urlPattern"`
+		URLPathPattern string `json:"urlPathPattern"`
+	} `json:"request"`
+	Response struct {
+		Status   int             `json:"status"`
+		JSONBody json.RawMessage `json:"jsonBody"`
+		Body     string          `json:"body"`
+	} `json:"response"`
+}
+
+var (
+	reviewsPathRe      = regexp.MustCompile(`/property/reviews/(\d+)`)
+	translationsPathRe = regexp.MustCompile(`/property/(\d+)/lang/`)
+	propertyPathRe     = regexp.MustCompile(`/property/(\d+)`)
+)
+
+// fromMapping unwraps the response body of a wiremock mapping and guesses its
+// kind and hotel from the request URL.
+func fromMapping(value []byte, source string, opts Options) []Record {
+	var m mapping
+	if err := json.Unmarshal(value, &m); err != nil {
+		return []Record{{Source: source, Err: fmt.Errorf("failed to decode wiremock mapping: %w", err)}}
+	}
+	if m.Response.Status != 0 && (m.Response.Status < 200 || m.Response.Status > 299) {
+		return []Record{{Source: source, Skipped: fmt.Sprintf("wiremock mapping of a %d response", m.Response.Status)}}
+	}
+
+	body := []byte(m.Response.JSONBody)
+	if len(body) == 0 {
+		body = []byte(m.Response.Body)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return []Record{{Source: source, Skipped: "wiremock mapping without a body"}}
+	}
+
+	url := m.Request.URL + m.Request.URLPath + m.Request.URLPattern + m.Request.URLPathPattern
+	hinted := opts
+	for _, hint := range []struct {
+		re   *regexp.Regexp
+		kind Kind
+	}{
+		{reviewsPathRe, KindReviews},
+		{translationsPathRe, KindTranslations},
+		{propertyPathRe, KindProperty},
+	} {
+		match := hint.re.FindStringSubmatch(url)
+		if match == nil {
+			continue
+		}
+		if hinted.Kind == "" {
+			hinted.Kind = hint.kind
+		}
+		if hinted.HotelID == 0 {
+			hinted.HotelID, _ = strconv.Atoi(match[1])
+		}
+		break
+	}
+	// Patterns such as /v3.0/property/reviews/[^/]+ name no hotel but still tell the kind.
+	if hinted.Kind == "" && strings.Contains(url, "/property/reviews/") {
+		hinted.Kind = KindReviews
+	} else if hinted.Kind == "" && strings.Contains(url, "/lang/") {
+		hinted.Kind = KindTranslations
+	}
+	return decodeValue(body, source, hinted)
+}
+
+// decodeValue turns one JSON value into records. Objects are properties,
+// wiremock mappings, {"hotel_id": ..., "reviews"|"translations": [...]}
+// envelopes or single reviews and translations; arrays hold reviews,
+// translations or properties.
+func decodeValue(value []byte, source string, opts Options) []Record {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 {
+		return []Record{{Source: source, Err: errors.New("empty payload")}}
+	}
+
+	switch value[0] {
+	case '{':
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(value, &keys); err != nil {
+			return []Record{{Source: source, Err: fmt.Errorf("failed to decode JSON: %w", err)}}
+		}
+		if _, ok := keys["response"]; ok {
+			if _, ok := keys["request"]; ok {
+				return fromMapping(value, source, opts)
+			}
+		}
+		// Property payloads carry a "reviews" field too, so only objects without a hotel name are envelopes.
+		_, named := keys["hotel_name"]
+		for _, kind := range []Kind{KindReviews, KindTranslations} {
+			list, ok := keys[string(kind)]
+			if !ok || named || (opts.Kind != "" && opts.Kind != kind) {
+				continue
+			}
+			enveloped := opts
+			enveloped.Kind = kind
+			if enveloped.HotelID == 0 {
+				_ = json.Unmarshal(keys["hotel_id"], &enveloped.HotelID)
+			}
+			return decodeList(list, source, enveloped)
+		}
+
+		kind := opts.Kind
+		if kind == "" {
+			kind = detectKind(keys)
+		}
+		if kind == KindProperty {
+			return []Record{decodeProperty(value, source)}
+		}
+		return decodeList(append(append([]byte{'['}, value...), ']'), source, Options{Kind: kind, HotelID: opts.HotelID})
+	case '[':
+		return decodeList(value, source, opts)
+	default:
+		return []Record{{Source: source, Err: errors.New("payload is neither a JSON object nor an array")}}
+	}
+}
+
+func detectKind(keys map[string]json.RawMessage) Kind {
+	has := func(key string) bool {
+		_, ok := keys[key]
+		return ok
+	}
+	switch {
+	case has("reviewer_name"), has("review_date"):
+		return KindReviews
+	case has("translated_text"), has("field_name"):
+		return KindTranslations
+	default:
+		return KindProperty
+	}
+}
+
+func decodeProperty(value []byte, source string) Record {
+	record := Record{Source: source, Kind: KindProperty}
+	property, err := client.ParseProperty(value)
+	if err != nil {
+		record.Err = fmt.Errorf("failed to parse property: %w", err)
+		return record
+	}
+	record.HotelID = property.HotelID
+	record.Property = property
+	record.Err = property.Validate()
+	return record
+}
+
+func decodeList(value []byte, source string, opts Options) []Record {
+	kind := opts.Kind
+	if kind == "" {
+		var items []map[string]json.RawMessage
+		if err := json.Unmarshal(value, &items); err != nil {
+			return []Record{{Source: source, Err: fmt.Errorf("failed to decode JSON array: %w", err)}}
+		}
+		if len(items) == 0 {
+			return []Record{{Source: source, Skipped: "empty array"}}
+		}
+		kind = detectKind(items[0])
+	}
+
+	switch kind {
+	case KindReviews:
+		reviews, err := client.ParseReviews(value)
+		if err != nil {
+			return []Record{{Source: source, Kind: kind, Err: fmt.Errorf("failed to parse reviews: %w", err)}}
+		}
+		return groupReviews(reviews, source, opts.HotelID)
+	case KindTranslations:
+		translations, err := client.ParseTranslations(value)
+		if err != nil {
+			return []Record{{Source: source, Kind: kind, Err: fmt.Errorf("failed to parse translations: %w", err)}}
+		}
+		return groupTranslations(translations, source, opts.HotelID)
+	default:
+		var items []json.RawMessage
+		if err := json.Unmarshal(value, &items); err != nil {
+			return []Record{{Source: source, Kind: kind, Err: fmt.Errorf("failed to decode JSON array: %w", err)}}
+		}
+		records := make([]Record, 0, len(items))
+		for i, item := range items {
+			records = append(records, decodeProperty(item, fmt.Sprintf("%s[%d]", source, i)))
+		}
+		return records
+	}
+}
+
+var errUnknownHotel = errors.New("hotel ID unknown: use -hotel-id or a {\"hotel_id\": ..., \"reviews\" or \"translations\": [...]} envelope")
+
+// groupReviews splits reviews by hotel. hotelID, when set, takes precedence
+// over the hotel_id of every review.
+func groupReviews(reviews []client.Review, source string, hotelID int) []Record {
+	byHotel := make(map[int]*Record)
+	var order []int
+	for i, review := range reviews {
+		id := hotelID
+		if id == 0 {
+			id = review.HotelID
+		}
+		record, ok := byHotel[id]
+		if !ok {
+			record = &Record{Source: source, Kind: KindReviews, HotelID: id}
+			byHotel[id] = record
+			order = append(order, id)
+		}
+		if err := review.Validate(); err != nil {
+			record.Err = errors.Join(record.Err, fmt.Errorf("review %d: %w", i, err))
+		}
+		record.Reviews = append(record.Reviews, review)
+	}
+	return collect(byHotel, order)
+}
+
+// groupTranslations splits translations by hotel. hotelID, when set, takes
+// precedence over the entity of every translation.
+func groupTranslations(translations []client.Translation, source string, hotelID int) []Record {
+	byHotel := make(map[int]*Record)
+	var order []int
+	for i, translation := range translations {
+		id := hotelID
+		if id == 0 && (translation.EntityType == "" || translation.EntityType == "hotel") {
+			id = translation.EntityID
+		}
+		record, ok := byHotel[id]
+		if !ok {
+			record = &Record{Source: source, Kind: KindTranslations, HotelID: id}
+			byHotel[id] = record
+			order = append(order, id)
+		}
+		if err := translation.Validate(); err != nil {
+			record.Err = errors.Join(record.Err, fmt.Errorf("translation %d: %w", i, err))
+		}
+		record.Translations = append(record.Translations, translation)
+	}
+	return collect(byHotel, order)
+}
+
+func collect(byHotel map[int]*Record, order []int) []Record {
+	records := make([]Record, 0, len(order))
+	for _, id := range order {
+		record := byHotel[id]
+		if id <= 0 {
+			record.Err = errors.Join(errUnknownHotel, record.Err)
+		}
+		records = append(records, *record)
+	}
+	return records
+}
+
+// IsNDJSON reports whether a file holds one JSON value per line, by extension.
+func IsNDJSON(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return true
+	default:
+		return false
+	}
+}
+
+// Files expands paths into the JSON and NDJSON files to import, walking
+// directories recursively in lexical order. "-" stands for stdin.
+func Files(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		if path == "-" {
+			files = append(files, path)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(p)) {
+			case ".json", ".ndjson", ".jsonl":
+				if !d.IsDir() {
+					found = append(found, p)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", path, err)
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+	return files, nil
+}
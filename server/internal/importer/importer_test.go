@@ -0,0 +1,198 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vrnvu/cupid/internal/client"
+)
+
+func decodeAll(t *testing.T, input string, opts Options) []Record {
+	t.Helper()
+	var records []Record
+	err := Decode(strings.NewReader(input), "input", opts, func(r Record) error {
+		records = append(records, r)
+		return nil
+	})
+	require.NoError(t, err)
+	return records
+}
+
+func TestDecode_WiremockMappings(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("..", "..", "..", "wiremock", "mappings")
+	files, err := Files([]string{dir})
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	byFile := make(map[string][]Record)
+	for _, file := range files {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		err = Decode(f, file, Options{}, func(r Record) error {
+			byFile[filepath.Base(file)] = append(byFile[filepath.Base(file)], r)
+			return nil
+		})
+		f.Close()
+		require.NoError(t, err)
+	}
+
+	require.Len(t, byFile["200.json"], 1)
+	property := byFile["200.json"][0]
+	require.NoError(t, property.Err)
+	assert.Equal(t, KindProperty, property.Kind)
+	assert.Equal(t, 1641879, property.HotelID)
+	assert.Equal(t, "The Z Hotel Covent Garden", property.Property.HotelName)
+
+	for _, name := range []string{"400.json", "500.json"} {
+		require.Len(t, byFile[name], 1)
+		assert.NoError(t, byFile[name][0].Err)
+		assert.Contains(t, byFile[name][0].Skipped, "wiremock mapping of a")
+	}
+}
+
+func TestDecode_Kinds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     Options
+		kind     Kind
+		hotelIDs []int
+		items    int
+	}{
+		{
+			name:     "reviews envelope",
+			input:    `{"hotel_id": 7, "reviews": [{"reviewer_name": "Ann", "rating": 5}, {"reviewer_name": "Bob", "rating": 4}]}`,
+			kind:     KindReviews,
+			hotelIDs: []int{7},
+			items:    2,
+		},
+		{
+			name:     "reviews array grouped by hotel_id",
+			input:    `[{"hotel_id": 1, "rating": 5, "reviewer_name": "A"}, {"hotel_id": 2, "rating": 3, "reviewer_name": "B"}, {"hotel_id": 1, "rating": 4, "reviewer_name": "C"}]`,
+			kind:     KindReviews,
+			hotelIDs: []int{1, 2},
+			items:    3,
+		},
+		{
+			name:     "translations array keyed by entity",
+			input:    `[{"entity_type": "hotel", "entity_id": 9, "language_code": "fr", "field_name": "name", "translated_text": "Hôtel"}]`,
+			kind:     KindTranslations,
+			hotelIDs: []int{9},
+			items:    1,
+		},
+		{
+			name:     "hotel ID option overrides the payload",
+			input:    `[{"entity_id": 9, "language_code": "es", "field_name": "name", "translated_text": "Hotel"}]`,
+			opts:     Options{HotelID: 42},
+			kind:     KindTranslations,
+			hotelIDs: []int{42},
+			items:    1,
+		},
+		{
+			name:     "wiremock reviews mapping takes the hotel from the URL",
+			input:    `{"request": {"urlPath": "/v3.0/property/reviews/55/10"}, "response": {"status": 200, "jsonBody": [{"rating": 4}]}}`,
+			kind:     KindReviews,
+			hotelIDs: []int{55},
+			items:    1,
+		},
+		{
+			name:     "single review object",
+			input:    `{"hotel_id": 3, "reviewer_name": "Ann", "rating": 2}`,
+			kind:     KindReviews,
+			hotelIDs: []int{3},
+			items:    1,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			records := decodeAll(t, tc.input, tc.opts)
+
+			var hotelIDs []int
+			items := 0
+			for _, r := range records {
+				require.NoError(t, r.Err)
+				assert.Equal(t, tc.kind, r.Kind)
+				hotelIDs = append(hotelIDs, r.HotelID)
+				items += len(r.Reviews) + len(r.Translations)
+			}
+			assert.Equal(t, tc.hotelIDs, hotelIDs)
+			assert.Equal(t, tc.items, items)
+		})
+	}
+}
+
+func TestDecode_ReportsRecordErrors(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Join([]string{
+		`{"hotel_id": 1, "hotel_name": "Valid"}`,
+		`{"hotel_id": 0, "hotel_name": ""}`,
+		`not json`,
+		`[{"reviewer_name": "Ann", "rating": 9}]`,
+		``,
+		`{"hotel_id": 2, "hotel_name": "Also valid"}`,
+	}, "\n")
+
+	records := decodeAll(t, input, Options{NDJSON: true})
+	require.Len(t, records, 5)
+
+	assert.NoError(t, records[0].Err)
+	assert.Equal(t, "input:1", records[0].Source)
+
+	var validationErr *client.ValidationError
+	assert.ErrorAs(t, records[1].Err, &validationErr)
+	assert.Equal(t, "input:2", records[1].Source)
+
+	assert.ErrorContains(t, records[2].Err, "neither a JSON object nor an array")
+	assert.Equal(t, "input:3", records[2].Source)
+
+	assert.ErrorIs(t, records[3].Err, errUnknownHotel)
+	assert.ErrorContains(t, records[3].Err, "review 0: invalid payload: rating 9 out of range 1-5")
+
+	assert.NoError(t, records[4].Err)
+	assert.Equal(t, "input:6", records[4].Source)
+	assert.Equal(t, 2, records[4].HotelID)
+}
+
+func TestDecode_ConcatenatedJSON(t *testing.T) {
+	t.Parallel()
+
+	records := decodeAll(t, `{"hotel_id": 1, "hotel_name": "A"} {"hotel_id": 2, "hotel_name": "B"}`, Options{})
+	require.Len(t, records, 2)
+	assert.Equal(t, "input:2", records[1].Source)
+	assert.Equal(t, 2, records[1].HotelID)
+}
+
+func TestFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"b.json", "a.ndjson", "notes.txt", filepath.Join("nested", "c.jsonl")} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0o644))
+	}
+
+	files, err := Files([]string{dir, "-"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "a.ndjson"),
+		filepath.Join(dir, "b.json"),
+		filepath.Join(dir, "nested", "c.jsonl"),
+		"-",
+	}, files)
+
+	_, err = Files([]string{filepath.Join(dir, "missing.json")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"too few fields", "0 2 * *"},
+		{"too many fields", "0 2 * * * *"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "0 24 * * *"},
+		{"day of month zero", "0 0 0 * *"},
+		{"inverted range", "0 5-2 * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"not a number", "a * * * *"},
+		{"unknown descriptor", "@sometimes"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := Parse(tc.expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday.
+	from := time.Date(2025, 1, 15, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2025, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{"hourly", "@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"nightly later today", "0 22 * * *", time.Date(2025, 1, 15, 22, 0, 0, 0, time.UTC)},
+		{"nightly tomorrow", "0 2 * * *", time.Date(2025, 1, 16, 2, 0, 0, 0, time.UTC)},
+		{"weekly on sunday", "0 3 * * 0", time.Date(2025, 1, 19, 3, 0, 0, 0, time.UTC)},
+		{"sunday as seven", "0 3 * * 7", time.Date(2025, 1, 19, 3, 0, 0, 0, time.UTC)},
+		{"step", "*/20 * * * *", time.Date(2025, 1, 15, 10, 40, 0, 0, time.UTC)},
+		{"list", "15,45 * * * *", time.Date(2025, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"range with step", "0 9-17/4 * * *", time.Date(2025, 1, 15, 13, 0, 0, 0, time.UTC)},
+		{"monthly", "@monthly", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month or day of week", "0 0 20 * 5", time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := Parse(tc.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, s.Next(from))
+		})
+	}
+}
+
+func TestSchedule_NextIsStrictlyAfter(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse("0 * * * *")
+	require.NoError(t, err)
+
+	onTheHour := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, onTheHour.Add(time.Hour), s.Next(onTheHour))
+}
+
+func TestSchedule_NextNeverMatches(t *testing.T) {
+	t.Parallel()
+
+	s, err := Parse("0 0 31 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+}